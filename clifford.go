@@ -15,6 +15,11 @@ import (
 // If the first argument passed to the CLI is `-h` or `--help`, Parse will
 // automatically call BuildHelp and exit the program.
 //
+// Behaviour can be adjusted by passing one or more Option values.
+//
+// The root struct must embed `Clifford`; if it does not, Parse returns a
+// DefinitionError without inspecting the arguments.
+//
 // Usage:
 //
 //	target := struct {
@@ -39,10 +44,142 @@ import (
 //	}
 var Parse = core.Parse
 
+// ParseArgs parses the given arguments into target, behaving exactly like
+// Parse but without reading os.Args. args excludes the program name, as in
+// os.Args[1:]. This suits tests, REPLs and servers that invoke a CLI
+// definition programmatically.
+//
+// Usage:
+//
+//	err := clifford.ParseArgs(&target, []string{"serve", "--port", "8080"})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+var ParseArgs = core.ParseArgs
+
+// New returns a Parser configured by opts. A Parser carries everything a parse
+// depends on, including the arguments (WithArgs), the output writer
+// (WithWriter) and the exit function (WithExitFunc), so tests and servers can
+// run independent parsers side by side without touching os.Args or other
+// globals. Parse and ParseArgs behave like a Parser created for the call.
+//
+// Usage:
+//
+//	p := clifford.New(
+//		clifford.WithArgs([]string{"serve", "--port", "8080"}),
+//		clifford.WithWriter(&buf),
+//		clifford.WithExitFunc(func(int) {}),
+//		clifford.WithColor(false),
+//		clifford.WithWidth(100),
+//	)
+//	err := p.Parse(&target)
+var New = core.New
+
+// Parser parses command lines with a fixed set of options. See New.
+type Parser = core.Parser
+
+// ParseAndRun parses command-line arguments into target like Parse, then calls
+// the Run method of the subcommand the user invoked, replacing the switch over
+// subcommand booleans that would otherwise follow Parse. The deepest invoked
+// command that implements Runner runs, falling back to its parents and then
+// the root; subcommands that were not invoked never run. Run receives the
+// context given by WithContext, or context.Background. Nothing runs when
+// parsing fails or help or version output is printed.
+//
+// Usage:
+//
+//	type Serve struct {
+//		clifford.Subcommand
+//		Port int `long:"port"`
+//	}
+//
+//	func (s *Serve) Run(ctx context.Context) error {
+//		return listen(ctx, s.Port)
+//	}
+//
+//	err := clifford.ParseAndRun(&target)
+var ParseAndRun = core.ParseAndRun
+
+// Runner is implemented by command structs that handle their own invocation.
+// See ParseAndRun.
+type Runner = core.Runner
+
+// Validate checks the CLI definition target without parsing any arguments
+// and returns a DefinitionError describing the first mistake it finds, such
+// as an unknown help mode (`type:"flagg"`) or a duplicate `pos` tag. Calling
+// it from a test catches definition typos before they reach users.
+//
+// Usage:
+//
+//	if err := clifford.Validate(&target); err != nil {
+//		log.Fatal(err)
+//	}
+var Validate = core.Validate
+
+// ParseInto parses command-line arguments into a freshly allocated copy of the
+// target's type and returns a pointer to it, leaving target untouched.
+//
+// The target acts purely as a definition, so a single definition value can be
+// shared across goroutines without its fields being overwritten by concurrent
+// parses. The returned value has the same type as target.
+//
+// Usage:
+//
+//	parsed, err := clifford.ParseInto(&definition)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	cli := parsed.(*CLI)
+var ParseInto = core.ParseInto
+
+// ParseResult parses command-line arguments into target exactly like Parse and
+// returns a Result describing the parse, which lets generic tooling inspect
+// fields by their dotted path without reflecting over the target itself.
+//
+// Usage:
+//
+//	res, err := clifford.ParseResult(&target)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	port, ok := res.Get("Serve.Port")
+//
+// Result.IsSet tells flags given on the command line apart from values that
+// came from defaults, the environment or config files, so that only flags the
+// user typed override a loaded configuration:
+//
+//	if res.IsSet("Serve.Port") {
+//		cfg.Port = port.(int)
+//	}
+var ParseResult = core.ParseResult
+
+// Result describes a completed parse. See ParseResult.
+type Result = core.Result
+
+// Report summarizes how an invocation was interpreted: the subcommand that
+// ran, each bound value with its source, any warnings and the parse time. It
+// is returned by Result.Report and suits logging in CI:
+//
+//	res, err := clifford.ParseResult(&target)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, b := range res.Report().Bound {
+//		log.Printf("%s = %q (from %s)", b.Path, b.Value, b.Source)
+//	}
+type Report = core.Report
+
+// BoundValue records the value a single field received and its source. See
+// Report.
+type BoundValue = core.BoundValue
+
 // BuildHelp generates and returns a formatted help message for a CLI tool
 // defined by the given struct pointer.
-// BuildHelp also takes in a boolean `long` parameter that, if set to true,
-// will print a more detailed help message with the long_about description
+// BuildHelp also takes in a boolean `long` parameter. When false it renders a
+// one-screen summary that lists option names without their descriptions, as
+// shown for `-h`. When true it renders the complete manual shown for `--help`:
+// full option descriptions, the `long_about` text and any `examples` declared
+// on the Clifford embedding. Multiple examples are separated by semicolons.
 //
 // The `target` must be a pointer to a struct that embeds a `Clifford` field
 // with a `name` tag. This tag specifies the CLI tool's name and is displayed
@@ -54,8 +191,10 @@ var Parse = core.Parse
 //   - A section for required arguments (based on `Required` tags)
 //   - A section for optional flags (based on `short` or `long` tags)
 //
-// If no `name` tag is found on any embedded `Clifford` field, the function
-// returns an error.
+// Options such as WithTranslator may be passed to adjust the rendered text.
+//
+// If no `name` tag is found, or the struct does not embed `Clifford` at all,
+// the usage line falls back to the base name of the running program (os.Args[0]).
 //
 // Example:
 //
@@ -81,6 +220,20 @@ var Parse = core.Parse
 //	fmt.Println(helpText)
 var BuildHelp = display.BuildHelp
 
+// WriteHelp renders the help message for target, exactly as BuildHelp does,
+// and writes it to w without exiting. This suits programs that handle
+// `--help` themselves or embed the help text in their own output.
+//
+// ANSI styling is kept only when w is a terminal, so help written to a file,
+// pipe or buffer is plain text.
+//
+// Example:
+//
+//	if err := clifford.WriteHelp(os.Stderr, &target, true); err != nil {
+//		log.Fatal(err)
+//	}
+var WriteHelp = display.WriteHelp
+
 // BuildVersion returns a formatted version string for the CLI tool defined
 // by the provided struct pointer.
 //
@@ -104,7 +257,64 @@ var BuildHelp = display.BuildHelp
 //	fmt.Println(version) // Output: mytool v1.2.3
 var BuildVersion = display.BuildVersion
 
+// GenBashCompletion writes a bash completion script for the CLI defined by the
+// given struct pointer to w.
+//
+// The generated script completes flags and subcommand names for whichever
+// (possibly nested) subcommand is being typed. Positional arguments tagged
+// `complete:"file"` or `complete:"dir"` complete file or directory paths.
+//
+// Example:
+//
+//	if err := clifford.GenBashCompletion(&target, os.Stdout); err != nil {
+//		log.Fatal(err)
+//	}
+//
+// The output can be sourced directly, e.g. `source <(mytool completion)`.
+var GenBashCompletion = display.GenBashCompletion
+
+// BuildJSONSchema returns a JSON Schema describing the options accepted by the
+// CLI defined by the given struct pointer, for validating configuration
+// against it. Flags are keyed by their long name and carry their type,
+// description, default and `choices` enum; required fields are listed under
+// `required` and subcommands are nested objects.
+//
+// Example:
+//
+//	schema, err := clifford.BuildJSONSchema(&target)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Println(schema)
+var BuildJSONSchema = display.BuildJSONSchema
+
+// Describe returns the command tree of the CLI defined by the given struct
+// pointer: each command's name, aliases and description, its flags and
+// positional arguments, and its subcommands. The tree holds only plain
+// values, so third parties can render their own help, generate man pages or
+// build completions without reflecting over the definition.
+//
+// Example:
+//
+//	spec, err := clifford.Describe(&target)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, sub := range spec.Subcommands {
+//		fmt.Println(sub.Name, "-", sub.Description)
+//	}
+var Describe = display.Describe
+
+// CommandSpec describes a command returned by Describe.
+type CommandSpec = display.CommandSpec
+
+// FlagSpec describes a flag of a CommandSpec.
+type FlagSpec = display.FlagSpec
+
+// ArgSpec describes a positional argument of a CommandSpec.
+type ArgSpec = display.ArgSpec
+
 // BuildHelpWithParent exposes the subcommand-aware help builder for callers/tests.
-func BuildHelpWithParent(parent any, subName string, subTarget any, long bool) (string, error) {
-	return display.BuildHelpWithParent(parent, subName, subTarget, long)
+func BuildHelpWithParent(parent any, subName string, subTarget any, long bool, opts ...Option) (string, error) {
+	return display.BuildHelpWithParent(parent, subName, subTarget, long, opts...)
 }
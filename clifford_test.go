@@ -1,10 +1,14 @@
 package clifford_test
 
 import (
+	"bytes"
+	"errors"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/chriso345/clifford"
+	clierrors "github.com/chriso345/clifford/errors"
 	"github.com/chriso345/gore/assert"
 	"github.com/chriso345/gore/vital"
 )
@@ -151,3 +155,42 @@ func TestParse_SubcommandProperHelp(t *testing.T) {
 	assert.StringContains(t, helper, "FILE     File to run")
 	assert.NotStringContains(t, helper, "DESC")
 }
+
+func TestParser_Options(t *testing.T) {
+	type cli struct {
+		clifford.Clifford `name:"app"`
+		clifford.Help
+
+		Port struct {
+			Value             int
+			clifford.Clifford `long:"port" desc:"Port the server listens on for incoming connections"`
+		}
+	}
+
+	target := cli{}
+	p := clifford.New(clifford.WithArgs([]string{"--port", "8080"}))
+	vital.Nil(t, p.Parse(&target))
+	assert.Equal(t, target.Port.Value, 8080)
+
+	// Help goes to the writer, and the exit function keeps the test running
+	var buf bytes.Buffer
+	p = clifford.New(
+		clifford.WithArgs([]string{"--help"}),
+		clifford.WithWriter(&buf),
+		clifford.WithExitFunc(func(int) {}),
+		clifford.WithColor(false),
+		clifford.WithWidth(40),
+	)
+	err := p.Parse(&cli{})
+	assert.True(t, errors.Is(err, clierrors.ErrHelpRequested))
+	assert.False(t, strings.Contains(buf.String(), "\033["))
+	assert.True(t, strings.Contains(buf.String(), "Port the server listens\n"))
+
+	// Forcing color keeps styling even though the writer is not a terminal
+	if _, set := os.LookupEnv("NO_COLOR"); !set {
+		buf.Reset()
+		p = clifford.New(clifford.WithArgs([]string{"--help"}), clifford.WithWriter(&buf), clifford.WithExitFunc(func(int) {}), clifford.WithColor(true))
+		assert.True(t, errors.Is(p.Parse(&cli{}), clierrors.ErrHelpRequested))
+		assert.True(t, strings.Contains(buf.String(), "\033["))
+	}
+}
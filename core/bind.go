@@ -0,0 +1,678 @@
+package core
+
+import (
+	"cmp"
+	"encoding"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chriso345/clifford/errors"
+	"github.com/chriso345/clifford/internal/common"
+)
+
+// binding is a single value-carrying field discovered on a command struct:
+// a top-level inline field, the Value of a container struct, or an inline
+// field declared inside a container.
+type binding struct {
+	name  string            // Go field name, used in error messages
+	path  string            // dotted field path relative to the command struct
+	tags  map[string]string // metadata collected from struct tags
+	value reflect.Value     // settable destination; invalid for marker-only fields
+}
+
+// isRest reports whether the binding is a catch-all positional that takes every
+// remaining argument verbatim, flags included.
+func (b binding) isRest() bool {
+	return b.tags["rest_positional"] == "true" && !b.isFlag() && !b.isTail()
+}
+
+// isVariadic reports whether the binding is a trailing positional slice, such
+// as the files of `app <FILES>...`, that takes every remaining positional.
+func (b binding) isVariadic() bool {
+	if b.isFlag() || b.isRest() || b.isTail() || b.isAssignment() || !b.value.IsValid() {
+		return false
+	}
+	return b.value.Kind() == reflect.Slice && !parsesItself(b.value.Type())
+}
+
+// isAssignment reports whether the binding is a map that collects every
+// KEY=VALUE positional, as in `app FOO=1 BAR=2 command`.
+func (b binding) isAssignment() bool {
+	return b.tags["assignment"] == "true" && !b.isFlag()
+}
+
+// isTail reports whether the binding is a mandatory command that takes every
+// argument after `--`, as in `app VAR=1 -- echo hi`.
+func (b binding) isTail() bool {
+	return b.tags["rest_required"] == "true" && !b.isFlag()
+}
+
+// isGlobal reports whether the binding is a flag tagged `global` or
+// `persistent`, which is also accepted after a subcommand.
+func (b binding) isGlobal() bool {
+	return b.isFlag() && (b.tags["global"] == "true" || b.tags["persistent"] == "true")
+}
+
+// isCount reports whether the binding is a count flag, whose value is the
+// number of times the flag was given, as in -vvv.
+func (b binding) isCount() bool {
+	return b.tags["count"] == "true" && b.isFlag()
+}
+
+// isSwitch reports whether the flags of the binding take no separate value:
+// booleans, count flags and flag.Value types whose IsBoolFlag returns true.
+func (b binding) isSwitch() bool {
+	if !b.value.IsValid() {
+		return false
+	}
+	if b.isBool() || b.isCount() {
+		return true
+	}
+	if b.value.CanAddr() {
+		bf, ok := b.value.Addr().Interface().(interface{ IsBoolFlag() bool })
+		return ok && bf.IsBoolFlag()
+	}
+	return false
+}
+
+// isBool reports whether the binding holds a boolean, directly or through a
+// pointer.
+func (b binding) isBool() bool {
+	return b.value.IsValid() && common.Indirect(b.value.Type()).Kind() == reflect.Bool
+}
+
+// isFlag reports whether the binding is addressed by a short or long flag
+// rather than by position.
+func (b binding) isFlag() bool {
+	return common.IsFlag(b.tags)
+}
+
+// collectBindings walks the command struct v in declaration order and returns
+// every field that can receive a value. Subcommand containers are skipped, as
+// they are dispatched separately.
+func collectBindings(v reflect.Value) []binding {
+	t := v.Type()
+	var bindings []binding
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+
+		// Skip meta fields like Clifford, Version, Help
+		if field.Type.Name() == "Clifford" || field.Type.Name() == "Version" || field.Type.Name() == "Help" {
+			continue
+		}
+		if field.Type.Kind() != reflect.Struct || parsesItself(field.Type) {
+			// Skip anonymous embedded non-struct markers (like Subcommand)
+			if field.Anonymous {
+				continue
+			}
+			// Inline primitive fields (e.g. MaxItems int `short:"n" long:"max-items"`)
+			bindings = append(bindings, binding{field.Name, field.Name, common.InlineTags(field), v.Field(i)})
+			continue
+		}
+
+		tags := common.GetTagsFromEmbedded(field.Type, field.Name)
+
+		// Marker-only embedded structs don't have a Value field; they only
+		// matter when marked Required, which can never be satisfied.
+		if _, ok := field.Type.FieldByName("Value"); !ok {
+			if tags["required"] == "true" {
+				bindings = append(bindings, binding{name: field.Name, path: field.Name, tags: tags})
+			}
+			continue
+		}
+
+		if tags["subcmd"] == "true" {
+			continue
+		}
+
+		subVal := v.Field(i)
+		bindings = append(bindings, binding{field.Name, field.Name, tags, subVal.FieldByName("Value")})
+
+		// Inline primitive fields declared inside the container
+		for j := 0; j < field.Type.NumField(); j++ {
+			inner := field.Type.Field(j)
+			if inner.Anonymous || inner.Name == "Value" || (inner.Type.Kind() == reflect.Struct && !parsesItself(inner.Type)) {
+				continue
+			}
+			bindings = append(bindings, binding{inner.Name, field.Name + "." + inner.Name, common.InlineTags(inner), subVal.Field(j)})
+		}
+	}
+
+	return bindings
+}
+
+// positionalQueue hands out positional arguments to bindings. Bindings tagged
+// `pos` take their fixed 1-based slot; the rest take the next unclaimed
+// positional in declaration order.
+type positionalQueue struct {
+	values  []string
+	claimed map[int]bool // indices reserved by explicit `pos` tags
+	next    int
+}
+
+// newPositionalQueue validates the `pos` tags on bindings and reserves their slots.
+func newPositionalQueue(values []string, bindings []binding) (*positionalQueue, error) {
+	q := &positionalQueue{values: values, claimed: map[int]bool{}}
+	owners := map[int]string{}
+	variadic := ""
+	for _, b := range bindings {
+		if !b.isFlag() && !b.isTail() && !b.isAssignment() {
+			if variadic != "" {
+				return nil, errors.NewDefinitionError(fmt.Sprintf("variadic positional %s must be the last positional", variadic))
+			}
+			if b.isVariadic() {
+				variadic = b.name
+			}
+		}
+		if (b.isRest() || b.isTail()) && (b.value.Kind() != reflect.Slice || b.value.Type().Elem().Kind() != reflect.String) {
+			return nil, errors.NewDefinitionError(fmt.Sprintf("rest positional %s must be a []string", b.name))
+		}
+		if b.isAssignment() && b.value.Kind() != reflect.Map {
+			return nil, errors.NewDefinitionError(fmt.Sprintf("assignment positional %s must be a map", b.name))
+		}
+		if b.isCount() && !b.value.CanInt() {
+			return nil, errors.NewDefinitionError(fmt.Sprintf("count flag %s must be an int", b.name))
+		}
+		if b.tags["pos"] == "" || b.isFlag() {
+			continue
+		}
+		pos, err := strconv.Atoi(b.tags["pos"])
+		if err != nil || pos < 1 {
+			return nil, errors.NewDefinitionError(fmt.Sprintf("invalid position %q on field %s", b.tags["pos"], b.name))
+		}
+		if owner, ok := owners[pos]; ok {
+			return nil, errors.NewDefinitionError(fmt.Sprintf("duplicate position %d on fields %s and %s", pos, owner, b.name))
+		}
+		owners[pos] = b.name
+		q.claimed[pos-1] = true
+	}
+	return q, nil
+}
+
+// splitAssignments removes the KEY=VALUE positionals from values and returns
+// them separately. The key is split off at sep, or "=" when sep is empty.
+func splitAssignments(values []string, sep string) (rest, assignments []string) {
+	if sep == "" {
+		sep = "="
+	}
+	for _, v := range values {
+		if key, _, ok := strings.Cut(v, sep); ok && key != "" {
+			assignments = append(assignments, v)
+			continue
+		}
+		rest = append(rest, v)
+	}
+	return rest, assignments
+}
+
+// take returns the positional value for b, reporting whether one was supplied.
+func (q *positionalQueue) take(b binding) (string, bool) {
+	if p := b.tags["pos"]; p != "" {
+		pos, _ := strconv.Atoi(p)
+		if pos <= len(q.values) {
+			return q.values[pos-1], true
+		}
+		return "", false
+	}
+	for q.next < len(q.values) && q.claimed[q.next] {
+		q.next++
+	}
+	if q.next < len(q.values) {
+		val := q.values[q.next]
+		q.next++
+		return val, true
+	}
+	return "", false
+}
+
+// takeRest returns every positional value not yet handed out.
+func (q *positionalQueue) takeRest() []string {
+	var rest []string
+	for ; q.next < len(q.values); q.next++ {
+		if !q.claimed[q.next] {
+			rest = append(rest, q.values[q.next])
+		}
+	}
+	return rest
+}
+
+// normalize applies the configured normalizer for b, then any tag-driven
+// normalization, to a raw value before conversion.
+func (s *parseState) normalize(b binding, value string) string {
+	if fn := s.cfg.Normalizers[b.name]; fn != nil {
+		value = fn(value)
+	}
+	if b.tags["expand_home"] == "true" {
+		value = expandHome(value)
+	}
+	return value
+}
+
+// expandHome replaces a leading `~` in path with the user's home directory.
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
+
+// flagValues returns every value given for the flags of b, short form first.
+func flagValues(b binding, argMap map[string][]string) []string {
+	var values []string
+	if short := b.tags["short"]; short != "" {
+		values = append(values, argMap["-"+short]...)
+	}
+	if long := b.tags["long"]; long != "" {
+		values = append(values, argMap["--"+long]...)
+	}
+	return values
+}
+
+// listSeparator returns the separator declared by the `separator` tag, or its
+// short form `sep`, that splits one value into several entries.
+func listSeparator(tags map[string]string) string {
+	if sep := tags["separator"]; sep != "" {
+		return sep
+	}
+	return tags["sep"]
+}
+
+// textUnmarshalerType is the encoding.TextUnmarshaler interface, which custom
+// value types implement to parse themselves.
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// flagValueType is the flag.Value interface from the standard library, whose
+// Set method is called once for every occurrence of a flag.
+var flagValueType = reflect.TypeOf((*flag.Value)(nil)).Elem()
+
+// isTextValue reports whether values of type t parse themselves through
+// encoding.TextUnmarshaler.
+func isTextValue(t reflect.Type) bool {
+	return reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// isFlagValue reports whether values of type t implement flag.Value.
+func isFlagValue(t reflect.Type) bool {
+	return reflect.PointerTo(t).Implements(flagValueType)
+}
+
+// parsesItself reports whether values of type t convert themselves from a
+// string instead of going through the built-in kinds.
+func parsesItself(t reflect.Type) bool {
+	return isFlagValue(t) || isTextValue(t)
+}
+
+// collects reports whether v gathers an entry from every occurrence of its
+// flag. flag.Value types do, receiving each one through Set; maps and slices
+// do unless they unmarshal themselves as a single value.
+func collects(v reflect.Value) bool {
+	if isFlagValue(v.Type()) {
+		return true
+	}
+	kind := v.Kind()
+	return (kind == reflect.Map || kind == reflect.Slice) && !isTextValue(v.Type())
+}
+
+// assign stores value in b, adding it as an entry when b is a map or
+// appending it when b is a slice. Maps and slices tagged with `separator`
+// accept several entries in a single value.
+func (s *parseState) assign(b binding, value string) error {
+	if !collects(b.value) || isFlagValue(b.value.Type()) {
+		return setValue(b.value, b.name, value)
+	}
+	switch b.value.Kind() {
+	case reflect.Map:
+	case reflect.Slice:
+		items := []string{value}
+		if sep := listSeparator(b.tags); sep != "" {
+			items = strings.Split(value, sep)
+		}
+		for _, item := range items {
+			if err := appendValue(b.value, b.name, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	sep := b.tags["kv_separator"]
+	if sep == "" {
+		sep = "="
+	}
+	entries := []string{value}
+	if pairSep := listSeparator(b.tags); pairSep != "" {
+		entries = strings.Split(value, pairSep)
+	}
+	for _, entry := range entries {
+		if err := setMapEntry(b.value, b.name, entry, sep); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setMapEntry splits entry on the first sep and stores the converted value
+// under its key, allocating the map if needed. Any later occurrences of sep
+// remain part of the value.
+func setMapEntry(f reflect.Value, name, entry, sep string) error {
+	if f.Type().Key().Kind() != reflect.String {
+		return errors.NewUnsupportedField(name, f.Type().String())
+	}
+	key, value, ok := strings.Cut(entry, sep)
+	if !ok {
+		return errors.NewParseError(fmt.Sprintf("invalid entry %q for %s: expected key%svalue", entry, name, sep))
+	}
+	elem := reflect.New(f.Type().Elem()).Elem()
+	if err := setValue(elem, name, strings.TrimSpace(value)); err != nil {
+		return err
+	}
+	if f.IsNil() {
+		f.Set(reflect.MakeMap(f.Type()))
+	}
+	f.SetMapIndex(reflect.ValueOf(strings.TrimSpace(key)).Convert(f.Type().Key()), elem)
+	return nil
+}
+
+// appendValue converts value to the element kind of the slice f and appends
+// it. Surrounding whitespace is ignored for every element kind except strings.
+func appendValue(f reflect.Value, name, value string) error {
+	elem := reflect.New(f.Type().Elem()).Elem()
+	if elem.Kind() != reflect.String {
+		value = strings.TrimSpace(value)
+	}
+	if err := setValue(elem, name, value); err != nil {
+		return err
+	}
+	f.Set(reflect.Append(f, elem))
+	return nil
+}
+
+// durationType is the type of time.Duration fields, which parse with
+// time.ParseDuration rather than as plain integers.
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// setValue converts value to the kind of f and stores it. Types implementing
+// flag.Value or encoding.TextUnmarshaler convert themselves, in that order of
+// preference, and pointers are allocated to hold the converted element. A
+// value that does not convert is reported as an InvalidValueError.
+func setValue(f reflect.Value, name, value string) error {
+	invalid := func() error { return errors.NewInvalidValue(name, value, f.Type().String()) }
+	if f.CanAddr() && isFlagValue(f.Type()) {
+		if err := f.Addr().Interface().(flag.Value).Set(value); err != nil {
+			return invalid()
+		}
+		return nil
+	}
+	if f.CanAddr() && isTextValue(f.Type()) {
+		if err := f.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value)); err != nil {
+			return invalid()
+		}
+		return nil
+	}
+	// Pointers stay nil until a value arrives, which then fills a fresh element
+	if f.Kind() == reflect.Pointer {
+		elem := reflect.New(f.Type().Elem())
+		if err := setValue(elem.Elem(), name, value); err != nil {
+			return err
+		}
+		f.Set(elem)
+		return nil
+	}
+	if f.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return invalid()
+		}
+		f.SetInt(int64(d))
+		return nil
+	}
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		intVal, err := strconv.ParseInt(value, 10, f.Type().Bits())
+		if err != nil {
+			return invalid()
+		}
+		f.SetInt(intVal)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		uintVal, err := strconv.ParseUint(value, 10, f.Type().Bits())
+		if err != nil {
+			return invalid()
+		}
+		f.SetUint(uintVal)
+	case reflect.Float32, reflect.Float64:
+		floatVal, err := strconv.ParseFloat(value, f.Type().Bits())
+		if err != nil {
+			return invalid()
+		}
+		f.SetFloat(floatVal)
+	case reflect.Bool:
+		boolVal, err := strconv.ParseBool(value)
+		if err != nil {
+			return invalid()
+		}
+		f.SetBool(boolVal)
+	default:
+		return errors.NewUnsupportedField(name, f.Kind().String())
+	}
+	return nil
+}
+
+// checkRange returns a RangeError when a number stored in b lies outside the
+// bounds of its `min` and `max` tags. Every element of a slice is checked.
+// Fields other than integers and floats are not range checked.
+func checkRange(b binding) error {
+	if b.tags["min"] == "" && b.tags["max"] == "" {
+		return nil
+	}
+	if err := checkBounds(b); err != nil {
+		return err
+	}
+	for _, v := range elements(b.value) {
+		for _, bound := range []string{"min", "max"} {
+			limit := b.tags[bound]
+			if limit == "" {
+				continue
+			}
+			order, ok, _ := compareNumber(v, limit)
+			if ok && ((bound == "min" && order < 0) || (bound == "max" && order > 0)) {
+				return errors.NewRangeError(b.name, fmt.Sprint(v.Interface()), bound, limit)
+			}
+		}
+	}
+	return nil
+}
+
+// elements returns the values stored in v for checking: each element of a
+// slice, the target of a non-nil pointer, or v itself.
+func elements(v reflect.Value) []reflect.Value {
+	switch v.Kind() {
+	case reflect.Slice:
+		values := make([]reflect.Value, 0, v.Len())
+		for i := range v.Len() {
+			values = append(values, v.Index(i))
+		}
+		return values
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil
+		}
+		return []reflect.Value{v.Elem()}
+	}
+	return []reflect.Value{v}
+}
+
+// checkBounds returns a DefinitionError when a `min` or `max` tag on b does
+// not parse as a number of the field's kind.
+func checkBounds(b binding) error {
+	if !b.value.IsValid() {
+		return nil
+	}
+	t := b.value.Type()
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	for _, bound := range []string{"min", "max"} {
+		limit := b.tags[bound]
+		if limit == "" {
+			continue
+		}
+		if _, _, err := compareNumber(reflect.New(t).Elem(), limit); err != nil {
+			return errors.NewDefinitionError(fmt.Sprintf("invalid %s %q on field %s", bound, limit, b.name))
+		}
+	}
+	return nil
+}
+
+// patterns caches the regular expressions compiled from `pattern` tags, keyed
+// by the tag value.
+var patterns sync.Map
+
+// compilePattern returns the compiled regular expression of the `pattern` tag
+// of b, or nil when it has none. A pattern that does not compile is reported
+// as a DefinitionError.
+func compilePattern(b binding) (*regexp.Regexp, error) {
+	pattern := b.tags["pattern"]
+	if pattern == "" {
+		return nil, nil
+	}
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, errors.NewDefinitionError(fmt.Sprintf("invalid pattern %q on field %s: %s", pattern, b.name, err))
+	}
+	patterns.Store(pattern, re)
+	return re, nil
+}
+
+// checkPattern returns a PatternError when a string stored in b does not match
+// its `pattern` tag. Every element of a string slice is checked.
+func checkPattern(b binding) error {
+	re, err := compilePattern(b)
+	if re == nil || err != nil {
+		return err
+	}
+	for _, v := range elements(b.value) {
+		if v.Kind() == reflect.String && !re.MatchString(v.String()) {
+			return errors.NewPatternError(b.name, v.String(), b.tags["pattern"])
+		}
+	}
+	return nil
+}
+
+// compareNumber compares the number v with limit, returning -1, 0 or 1. ok is
+// false when v is not an integer or float; err reports a limit that does not
+// parse as v's kind.
+func compareNumber(v reflect.Value, limit string) (order int, ok bool, err error) {
+	if v.Type() == durationType {
+		return 0, false, nil
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		l, err := strconv.ParseInt(limit, 10, 64)
+		return cmp.Compare(v.Int(), l), true, err
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		l, err := strconv.ParseUint(limit, 10, 64)
+		return cmp.Compare(v.Uint(), l), true, err
+	case reflect.Float32, reflect.Float64:
+		l, err := strconv.ParseFloat(limit, 64)
+		return cmp.Compare(v.Float(), l), true, err
+	}
+	return 0, false, nil
+}
+
+// declaration lists the flags and subcommands a command struct declares.
+type declaration struct {
+	flags       map[string]bool // flags such as "-n" and "--name"
+	switches    map[string]bool // flags that take no separate value, such as booleans
+	counts      map[string]bool // flags of count bindings, which never take a value
+	globals     map[string]bool // flags of global bindings, also accepted after a subcommand
+	subcommands map[string]bool // subcommand names
+	restAt      int             // index of the positional that starts a rest positional, or -1
+	tail        bool            // whether a rest_required field takes the arguments after "--"
+}
+
+// takesNoValue reports whether flag never takes the following argument as its
+// value. Undeclared help and version flags are switches too.
+func (d declaration) takesNoValue(flag string) bool {
+	if d.flags[flag] {
+		return d.switches[flag]
+	}
+	switch flag {
+	case "-h", "--help", "-v", "--version":
+		return true
+	}
+	return false
+}
+
+// declare returns the declaration of the command struct target.
+func declare(target any) declaration {
+	d := declaration{flags: map[string]bool{}, switches: map[string]bool{}, counts: map[string]bool{}, globals: map[string]bool{}, subcommands: map[string]bool{}, restAt: -1}
+	if !common.IsStructPtr(target) {
+		return d
+	}
+	positionals := 0
+	for _, b := range collectBindings(reflect.ValueOf(target).Elem()) {
+		if b.isRest() && d.restAt < 0 {
+			d.restAt = positionals
+		}
+		if b.isTail() {
+			d.tail = true
+			continue
+		}
+		if b.isAssignment() {
+			continue
+		}
+		if !b.isFlag() {
+			positionals++
+		}
+		if short := b.tags["short"]; short != "" {
+			d.flags["-"+short] = true
+			d.switches["-"+short] = b.isSwitch()
+			d.counts["-"+short] = b.isCount()
+			d.globals["-"+short] = b.isGlobal()
+		}
+		if long := b.tags["long"]; long != "" {
+			d.flags["--"+long] = true
+			d.switches["--"+long] = b.isSwitch()
+			d.counts["--"+long] = b.isCount()
+			d.globals["--"+long] = b.isGlobal()
+		}
+		if on, off, ok := common.PairFlags(b.tags, b.name); ok {
+			d.flags[on], d.switches[on], d.globals[on] = true, true, b.isGlobal()
+			d.flags[off], d.switches[off], d.globals[off] = true, true, b.isGlobal()
+		}
+		if neg := common.NegatedFlag(b.tags); neg != "" && b.isBool() {
+			d.flags[neg], d.switches[neg], d.globals[neg] = true, true, b.isGlobal()
+		}
+	}
+	t := common.GetStructType(target)
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if field.Type.Kind() != reflect.Struct {
+			continue
+		}
+		if tags := common.GetTagsFromEmbedded(field.Type, field.Name); tags["subcmd"] == "true" {
+			d.subcommands[common.SubcommandName(tags, field.Name)] = true
+			for _, alias := range common.SubcommandAliases(tags) {
+				d.subcommands[alias] = true
+			}
+		}
+	}
+	return d
+}
@@ -0,0 +1,39 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+var debugOutput io.Writer = os.Stderr // Mockable for testing
+
+// debugf writes a diagnostic line about the parse to stderr when the
+// CLIFFORD_DEBUG environment variable holds a true value such as 1. It is a
+// developer aid for tracing why a flag does or does not bind.
+func debugf(format string, args ...any) {
+	if envBool(os.Getenv("CLIFFORD_DEBUG")) != "true" {
+		return
+	}
+	fmt.Fprintf(debugOutput, "clifford: "+format+"\n", args...)
+}
+
+// debugValue returns value as it may appear in diagnostics, masking the
+// values of fields tagged `secret:"true"`.
+func debugValue(b binding, value string) string {
+	if b.tags["secret"] == "true" {
+		return "****"
+	}
+	return value
+}
+
+// givenFlags returns the flags in argIndex in command-line order.
+func givenFlags(argIndex map[string]int) []string {
+	flags := make([]string, 0, len(argIndex))
+	for flag := range argIndex {
+		flags = append(flags, flag)
+	}
+	sort.Slice(flags, func(i, j int) bool { return argIndex[flags[i]] < argIndex[flags[j]] })
+	return flags
+}
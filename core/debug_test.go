@@ -0,0 +1,75 @@
+package core
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/chriso345/clifford/internal/options"
+	"github.com/chriso345/gore/assert"
+)
+
+type debugCLI struct {
+	Clifford `name:"app"`
+
+	Serve struct {
+		Subcommand
+		Port struct {
+			Value    int `default:"8080"`
+			Clifford `long:"port"`
+		}
+		Token struct {
+			Value    string
+			Clifford `long:"token" secret:"true"`
+		}
+	}
+}
+
+func TestDebug_EnabledByEnv(t *testing.T) {
+	old := debugOutput
+	defer func() { debugOutput = old }()
+	var buf bytes.Buffer
+	debugOutput = &buf
+
+	t.Setenv("CLIFFORD_DEBUG", "1")
+	assert.Nil(t, ParseArgs(&debugCLI{}, []string{"serve", "--token", "hunter2"}))
+
+	out := buf.String()
+	assert.True(t, strings.Contains(out, `clifford: dispatch to subcommand "serve"`))
+	assert.True(t, strings.Contains(out, `clifford: bind Serve.Port = "8080" from default`))
+	assert.True(t, strings.Contains(out, `clifford: bind Serve.Token = "****" from command line`))
+	assert.False(t, strings.Contains(out, "hunter2"))
+}
+
+func TestDebug_InlineSecret(t *testing.T) {
+	old := debugOutput
+	defer func() { debugOutput = old }()
+	var buf bytes.Buffer
+	debugOutput = &buf
+
+	type cli struct {
+		Clifford `name:"app"`
+
+		Pass string `long:"pass" secret:"true"`
+	}
+
+	t.Setenv("CLIFFORD_DEBUG", "1")
+	c := cli{}
+	res, err := ParseResult(&c, options.WithArgs([]string{"--pass", "hunter2"}))
+	assert.Nil(t, err)
+	assert.Equal(t, c.Pass, "hunter2")
+	assert.Equal(t, res.Report().Bound[0], BoundValue{Path: "Pass", Value: "****", Source: "command line"})
+	assert.True(t, strings.Contains(buf.String(), `clifford: bind Pass = "****" from command line`))
+	assert.False(t, strings.Contains(buf.String(), "hunter2"))
+}
+
+func TestDebug_OffByDefault(t *testing.T) {
+	old := debugOutput
+	defer func() { debugOutput = old }()
+	var buf bytes.Buffer
+	debugOutput = &buf
+
+	t.Setenv("CLIFFORD_DEBUG", "")
+	assert.Nil(t, ParseArgs(&debugCLI{}, []string{"serve"}))
+	assert.Equal(t, buf.String(), "")
+}
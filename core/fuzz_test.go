@@ -0,0 +1,100 @@
+package core
+
+import (
+	stderrs "errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	clierr "github.com/chriso345/clifford/errors"
+	"github.com/chriso345/gore/assert"
+)
+
+type fuzzCLI struct {
+	Clifford `name:"app"`
+	Version  `version:"1.0.0"`
+	Help     `type:"both"`
+
+	File struct {
+		Value    string
+		Clifford `short:"f" long:"file"`
+	}
+	Count struct {
+		Value    int
+		Clifford `short:"c" long:"count" default:"1"`
+	}
+	Ratio struct {
+		Value    float64
+		Clifford `long:"ratio"`
+	}
+	Verbose bool `short:"v" long:"verbose"`
+
+	Serve struct {
+		Subcommand `name:"serve"`
+		Help       `type:"both"`
+		Port       struct {
+			Value    int
+			Clifford `long:"port" required_if:"tls"`
+		}
+		TLS bool `long:"tls"`
+	}
+	Remote struct {
+		Subcommand
+		Add struct {
+			Subcommand
+			Name struct {
+				Value string
+				Required
+			}
+		}
+	}
+}
+
+func TestParseIntoNew_HelpAndVersionSentinels(t *testing.T) {
+	typ := reflect.TypeOf(fuzzCLI{})
+
+	_, err := ParseIntoNew(typ, []string{"--help"})
+	assert.True(t, stderrs.Is(err, clierr.ErrHelpRequested))
+
+	_, err = ParseIntoNew(typ, []string{"serve", "help"})
+	assert.True(t, stderrs.Is(err, clierr.ErrHelpRequested))
+
+	_, err = ParseIntoNew(typ, []string{"--version"})
+	assert.True(t, stderrs.Is(err, clierr.ErrVersionRequested))
+
+	parsed, err := ParseIntoNew(typ, []string{"-f", "in.txt", "-c", "3"})
+	assert.Nil(t, err)
+	cli := parsed.(*fuzzCLI)
+	assert.Equal(t, cli.File.Value, "in.txt")
+	assert.Equal(t, cli.Count.Value, 3)
+}
+
+func TestParseIntoNew_InvalidType(t *testing.T) {
+	_, err := ParseIntoNew(reflect.TypeOf(42), nil)
+	var pe clierr.ParseError
+	assert.True(t, stderrs.As(err, &pe))
+}
+
+func FuzzParseIntoNew(f *testing.F) {
+	for _, seed := range []string{
+		"",
+		"-f in.txt --count 3 -v",
+		"serve --port 80 --tls",
+		"serve --tls",
+		"remote add origin",
+		"help serve",
+		"serve help",
+		"version",
+		"-- --weird",
+		"srve -c",
+		"--ratio nan -c -1",
+	} {
+		f.Add(seed)
+	}
+
+	typ := reflect.TypeOf(fuzzCLI{})
+	f.Fuzz(func(t *testing.T, line string) {
+		// Any error is acceptable; the parser must simply never panic or exit
+		_, _ = ParseIntoNew(typ, strings.Fields(line))
+	})
+}
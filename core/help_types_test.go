@@ -158,3 +158,77 @@ func TestBothHelpModesExit(t *testing.T) {
 
 	_ = Parse(&target)
 }
+
+// Test that `app version` prints the version and exits when Version is embedded
+func TestVersionSubcommandExits(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	os.Args = []string{"app", "version"}
+
+	target := struct {
+		Clifford `name:"app"`
+		Version  `version:"1.4.0"`
+
+		Serve struct {
+			Subcommand
+		}
+	}{}
+
+	oldExit := osExit
+	defer func() { osExit = oldExit }()
+	exited := false
+	osExit = func(code int) { exited = true; panic("os.Exit") }
+
+	// capture stdout
+	r, w, _ := os.Pipe()
+	oldOut := os.Stdout
+	os.Stdout = w
+	defer func() {
+		if err := w.Close(); err != nil {
+			t.Fatalf("close pipe: %v", err)
+		}
+		os.Stdout = oldOut
+	}()
+
+	defer func() {
+		os.Stdout = oldOut
+		if rec := recover(); rec == nil {
+			t.Fatalf("expected os.Exit panic")
+		}
+		buf := make([]byte, 4096)
+		n, _ := r.Read(buf)
+		out := string(buf[:n])
+		if !exited {
+			t.Fatalf("expected osExit to be called")
+		}
+		if !strings.Contains(out, "app v1.4.0") {
+			t.Fatalf("version output missing; got: %q", out)
+		}
+	}()
+
+	_ = Parse(&target)
+}
+
+// Test that a user-defined `version` subcommand is not shadowed by the built-in form
+func TestVersionSubcommandNotShadowed(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	os.Args = []string{"app", "version"}
+
+	target := struct {
+		Clifford `name:"app"`
+		Version  `version:"1.4.0"`
+
+		ShowVersion struct {
+			Subcommand `name:"version"`
+		}
+	}{}
+
+	err := Parse(&target)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bool(target.ShowVersion.Subcommand) {
+		t.Fatalf("expected user-defined version subcommand to be dispatched")
+	}
+}
@@ -110,3 +110,25 @@ func TestParse_SubcommandWithInlineFlags(t *testing.T) {
 	assert.Equal(t, cli.Menu.MaxItems, 9)
 	assert.Equal(t, cli.Menu.DryRun, true)
 }
+
+func TestParse_InlineTagsStayOnTheirField(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	os.Args = []string{"cmd", "--menu", "pizza", "--limit", "5"}
+
+	// The tags of Limit belong to Limit alone, not to the Menu container
+	cli := struct {
+		Clifford `name:"myapp"`
+
+		Menu struct {
+			Value    string
+			Clifford `long:"menu"`
+			Limit    int `long:"limit" desc:"Maximum items"`
+		}
+	}{}
+
+	err := Parse(&cli)
+	assert.Nil(t, err)
+	assert.Equal(t, cli.Menu.Value, "pizza")
+	assert.Equal(t, cli.Menu.Limit, 5)
+}
@@ -0,0 +1,491 @@
+package core
+
+import (
+	"bytes"
+	stderrs "errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	clierr "github.com/chriso345/clifford/errors"
+	"github.com/chriso345/clifford/internal/options"
+	"github.com/chriso345/gore/assert"
+)
+
+// captureStderr runs fn and returns everything it wrote to os.Stderr.
+func captureStderr(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe: %v", err)
+	}
+	oldErr := os.Stderr
+	os.Stderr = w
+	fn()
+	os.Stderr = oldErr
+	if err := w.Close(); err != nil {
+		t.Fatalf("close pipe: %v", err)
+	}
+	out, _ := io.ReadAll(r)
+	return string(out)
+}
+
+type missingArgCLI struct {
+	Clifford `name:"app"`
+
+	File struct {
+		Value string
+		Required
+	}
+}
+
+func TestParse_HelpOnErrorPrintsHelp(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	os.Args = []string{"app"}
+
+	var err error
+	out := captureStderr(t, func() {
+		err = Parse(&missingArgCLI{}, options.WithHelpOnError())
+	})
+	assert.NotNil(t, err)
+	assert.True(t, strings.Contains(out, "Usage:"))
+}
+
+func TestParse_QuietErrorsOverridesHelpOnError(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	os.Args = []string{"app"}
+
+	var err error
+	out := captureStderr(t, func() {
+		err = Parse(&missingArgCLI{}, options.WithHelpOnError(), options.WithQuietErrors())
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, out, "")
+}
+
+func TestParse_PlusFlags(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	type cli struct {
+		Clifford `name:"app"`
+
+		Extended struct {
+			Value    bool `default:"true"`
+			Clifford `short:"x"`
+		}
+		Input struct {
+			Value string
+		}
+	}
+
+	os.Args = []string{"app", "+x", "file.txt"}
+	off := cli{}
+	err := Parse(&off, options.WithPlusFlags())
+	assert.Nil(t, err)
+	assert.False(t, off.Extended.Value)
+	assert.Equal(t, off.Input.Value, "file.txt")
+
+	os.Args = []string{"app", "+x", "-x"}
+	on := cli{}
+	err = Parse(&on, options.WithPlusFlags())
+	assert.Nil(t, err)
+	assert.True(t, on.Extended.Value)
+}
+
+func TestParse_JSONStdin(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	type cli struct {
+		Clifford `name:"app"`
+
+		Serve struct {
+			Subcommand
+			Port struct {
+				Value    int `default:"8080"`
+				Clifford `long:"port"`
+			}
+			Host struct {
+				Value    string `default:"localhost"`
+				Clifford `long:"host"`
+			}
+		}
+	}
+
+	// stdin overrides defaults
+	os.Args = []string{"app", "serve"}
+	fromStdin := cli{}
+	err := Parse(&fromStdin, options.WithJSONStdin(), options.WithStdin(bytes.NewBufferString(`{"port": 80}`)))
+	assert.Nil(t, err)
+	assert.Equal(t, fromStdin.Serve.Port.Value, 80)
+	assert.Equal(t, fromStdin.Serve.Host.Value, "localhost")
+
+	// flags override stdin
+	os.Args = []string{"app", "serve", "--port", "9000"}
+	fromFlag := cli{}
+	err = Parse(&fromFlag, options.WithJSONStdin(), options.WithStdin(bytes.NewBufferString(`{"port": 80}`)))
+	assert.Nil(t, err)
+	assert.Equal(t, fromFlag.Serve.Port.Value, 9000)
+}
+
+func TestParse_Normalizers(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	t.Setenv("HOME", "/home/tester")
+	os.Args = []string{"app", "--path", "  ~/data  ", "--level", "DEBUG"}
+
+	cli := struct {
+		Clifford `name:"app"`
+
+		Path struct {
+			Value    string
+			Clifford `long:"path" expand_home:"true"`
+		}
+		Level string `long:"level"`
+	}{}
+
+	err := Parse(&cli,
+		options.WithNormalizer("Path", strings.TrimSpace),
+		options.WithNormalizer("Level", strings.ToLower),
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, cli.Path.Value, "/home/tester/data")
+	assert.Equal(t, cli.Level, "debug")
+}
+
+func TestParse_AvailableCommands(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	os.Args = []string{"app", "xyz"}
+
+	cli := struct {
+		Clifford `name:"app"`
+
+		Serve struct {
+			Subcommand
+		}
+		Status struct {
+			Subcommand
+		}
+	}{}
+
+	err := Parse(&cli, options.WithAvailableCommands())
+	assert.NotNil(t, err)
+	var ue clierr.UnknownSubcommandError
+	assert.True(t, stderrs.As(err, &ue))
+	assert.Equal(t, len(ue.Available), 2)
+	assert.True(t, strings.Contains(err.Error(), "available: serve, status"))
+}
+
+func TestParse_TranslatedError(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	os.Args = []string{"app"}
+
+	translator := func(key, text string) string {
+		if key == "error.missing_arg" {
+			return "argument manquant"
+		}
+		return text
+	}
+
+	err := Parse(&missingArgCLI{}, options.WithTranslator(translator))
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "argument manquant")
+	var me clierr.MissingArgError
+	assert.True(t, stderrs.As(err, &me))
+	assert.Equal(t, me.Field, "File")
+}
+
+func TestParse_RootOnlyHelp(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	oldExit := osExit
+	defer func() { osExit = oldExit }()
+	exited := false
+	osExit = func(code int) { exited = true }
+
+	type cli struct {
+		Clifford `name:"app"`
+		Help
+
+		Serve struct {
+			Subcommand
+			Help `type:"both"`
+			Port struct {
+				Value    int
+				Clifford `long:"port"`
+			}
+		}
+	}
+
+	// After a subcommand, --help is no longer intercepted
+	os.Args = []string{"app", "serve", "--help", "--port", "80"}
+	c := cli{}
+	err := Parse(&c, options.WithRootOnlyHelp())
+	assert.Nil(t, err)
+	assert.False(t, exited)
+	assert.True(t, bool(c.Serve.Subcommand))
+	assert.Equal(t, c.Serve.Port.Value, 80)
+
+	// At the root, --help still prints help and exits
+	os.Args = []string{"app", "--help"}
+	r, w, _ := os.Pipe()
+	oldOut := os.Stdout
+	os.Stdout = w
+	err = Parse(&cli{}, options.WithRootOnlyHelp())
+	os.Stdout = oldOut
+	_ = w.Close()
+	out, _ := io.ReadAll(r)
+	assert.Nil(t, err)
+	assert.True(t, exited)
+	assert.True(t, strings.Contains(string(out), "Usage:"))
+}
+
+func TestParse_ConfigFilesLayered(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.json")
+	prod := filepath.Join(dir, "prod.json")
+	assert.Nil(t, os.WriteFile(base, []byte(`{"host": "localhost", "port": 8080, "debug": true}`), 0o644))
+	assert.Nil(t, os.WriteFile(prod, []byte(`{"host": "example.com"}`), 0o644))
+
+	type cli struct {
+		Clifford `name:"app"`
+
+		Host struct {
+			Value    string
+			Clifford `long:"host"`
+		}
+		Port struct {
+			Value    int `default:"80"`
+			Clifford `long:"port"`
+		}
+		Debug struct {
+			Value    bool
+			Clifford `long:"debug"`
+		}
+	}
+
+	os.Args = []string{"app"}
+	c := cli{}
+	err := Parse(&c, options.WithConfigFiles(base, filepath.Join(dir, "missing.json"), prod))
+	assert.Nil(t, err)
+	assert.Equal(t, c.Host.Value, "example.com")
+	assert.Equal(t, c.Port.Value, 8080)
+	assert.True(t, c.Debug.Value)
+
+	// The command line still wins over every config file
+	os.Args = []string{"app", "--port", "9090"}
+	c = cli{}
+	err = Parse(&c, options.WithConfigFiles(base, prod))
+	assert.Nil(t, err)
+	assert.Equal(t, c.Port.Value, 9090)
+}
+
+func TestParse_ValidateHook(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	type serveCmd struct {
+		Subcommand
+		Host struct {
+			Value    string
+			Clifford `long:"host"`
+		}
+		Port struct {
+			Value    int
+			Clifford `long:"port"`
+		}
+	}
+	type cli struct {
+		Clifford `name:"app"`
+
+		Serve serveCmd
+	}
+
+	errPair := stderrs.New("host and port must be set together")
+	validate := options.WithValidate("serve", func(cmd any) error {
+		serve := cmd.(*serveCmd)
+		if (serve.Host.Value == "") != (serve.Port.Value == 0) {
+			return errPair
+		}
+		return nil
+	})
+
+	os.Args = []string{"app", "serve", "--host", "example.com"}
+	err := Parse(&cli{}, validate)
+	assert.NotNil(t, err)
+	var ve clierr.ValidationError
+	assert.True(t, stderrs.As(err, &ve))
+	assert.Equal(t, ve.Command, "serve")
+	assert.True(t, stderrs.Is(err, errPair))
+	assert.True(t, stderrs.Is(err, clierr.ErrValidation))
+	assert.Equal(t, err.Error(), "serve: host and port must be set together")
+
+	os.Args = []string{"app", "serve", "--host", "example.com", "--port", "80"}
+	c := cli{}
+	err = Parse(&c, validate)
+	assert.Nil(t, err)
+	assert.Equal(t, c.Serve.Port.Value, 80)
+}
+
+func TestParse_UsageOnMissing(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	oldExit := osExit
+	defer func() { osExit = oldExit }()
+	code := -1
+	osExit = func(c int) { code = c }
+
+	os.Args = []string{"app"}
+	var err error
+	out := captureStderr(t, func() {
+		err = Parse(&missingArgCLI{}, options.WithUsageOnMissing())
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, code, 2)
+	assert.True(t, strings.Contains(out, "Usage: app <FILE>"))
+	assert.True(t, strings.Contains(out, "missing required argument: File"))
+
+	// Missing arguments of a subcommand are returned without output
+	type cli struct {
+		Clifford `name:"app"`
+
+		Get struct {
+			Subcommand
+			Key struct {
+				Value string
+				Required
+			}
+		}
+	}
+	code = -1
+	os.Args = []string{"app", "get"}
+	out = captureStderr(t, func() {
+		err = Parse(&cli{}, options.WithUsageOnMissing())
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, code, -1)
+	assert.Equal(t, out, "")
+}
+
+func TestParse_ErrorOutput(t *testing.T) {
+	var codes []int
+	exit := options.WithExitFunc(func(code int) { codes = append(codes, code) })
+
+	var buf bytes.Buffer
+	out := captureStderr(t, func() {
+		_ = ParseArgs(&missingArgCLI{}, nil, exit, options.WithUsageOnMissing(), options.WithErrorOutput(&buf))
+	})
+	assert.Equal(t, out, "")
+	assert.True(t, strings.Contains(buf.String(), "Usage: app <FILE>"))
+	assert.True(t, strings.HasSuffix(buf.String(), "missing required argument: File\n"))
+	assert.Equal(t, codes[0], 2)
+
+	// The writer is not a terminal, so the help is plain unless color is forced
+	assert.False(t, strings.Contains(buf.String(), "\x1b["))
+
+	buf.Reset()
+	out = captureStderr(t, func() {
+		_ = ParseArgs(&missingArgCLI{}, nil, options.WithHelpOnError(), options.WithErrorOutput(&buf))
+	})
+	assert.Equal(t, out, "")
+	assert.True(t, strings.Contains(buf.String(), "Usage: app <FILE>"))
+}
+
+func TestParse_OutputWriter(t *testing.T) {
+	oldExit := osExit
+	defer func() { osExit = oldExit }()
+	exited := false
+	osExit = func(code int) { exited = true }
+
+	type cli struct {
+		Clifford `name:"app"`
+		Version  `version:"1.2.3"`
+		Help
+	}
+
+	var buf bytes.Buffer
+	err := ParseArgs(&cli{}, []string{"--version"}, options.WithOutput(&buf))
+	assert.Nil(t, err)
+	assert.True(t, exited)
+	assert.Equal(t, buf.String(), "app v1.2.3\n")
+
+	buf.Reset()
+	err = ParseArgs(&cli{}, []string{"--help"}, options.WithOutput(&buf))
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(buf.String(), "Usage:"))
+}
+
+func TestParse_ExitFunc(t *testing.T) {
+	type cli struct {
+		Clifford `name:"app"`
+		Version  `version:"1.2.3"`
+		Help
+
+		Name string `pos:"1" required:"true"`
+	}
+
+	var codes []int
+	exit := options.WithExitFunc(func(code int) { codes = append(codes, code) })
+
+	var buf bytes.Buffer
+	err := ParseArgs(&cli{}, []string{"--help"}, exit, options.WithOutput(&buf))
+	assert.True(t, stderrs.Is(err, clierr.ErrHelpRequested))
+	assert.True(t, strings.Contains(buf.String(), "Usage:"))
+
+	err = ParseArgs(&cli{}, []string{"--version"}, exit, options.WithOutput(&buf))
+	assert.True(t, stderrs.Is(err, clierr.ErrVersionRequested))
+
+	captureStderr(t, func() {
+		err = ParseArgs(&cli{}, nil, exit, options.WithUsageOnMissing())
+	})
+	var me clierr.MissingArgError
+	assert.True(t, stderrs.As(err, &me))
+	assert.Equal(t, len(codes), 3)
+	assert.Equal(t, codes[0], 0)
+	assert.Equal(t, codes[2], 2)
+}
+
+func TestParse_FlagsFile(t *testing.T) {
+	type cli struct {
+		Clifford `name:"app"`
+
+		Verbose bool `long:"verbose"`
+		Serve   struct {
+			Subcommand
+			Port int    `long:"port"`
+			Host string `long:"host"`
+		}
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "serve.flags")
+	content := "# defaults for serve\n--port 8080\n\n--host=example.com\n"
+	assert.Nil(t, os.WriteFile(path, []byte(content), 0o644))
+
+	c := cli{}
+	err := ParseArgs(&c, []string{"--verbose", "serve", "--port", "9090", "--flags-file", path}, options.WithFlagsFile())
+	assert.Nil(t, err)
+	assert.True(t, c.Verbose)
+	assert.Equal(t, c.Serve.Port, 9090)
+	assert.Equal(t, c.Serve.Host, "example.com")
+
+	// Without the option the flag is unknown
+	err = ParseArgs(&cli{}, []string{"serve", "--flags-file", path})
+	var ue clierr.UnknownFlagError
+	assert.True(t, stderrs.As(err, &ue))
+
+	err = ParseArgs(&cli{}, []string{"serve", "--flags-file", filepath.Join(dir, "missing")}, options.WithFlagsFile())
+	assert.NotNil(t, err)
+}
@@ -1,37 +1,169 @@
 package core
 
 import (
+	stderrors "errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"reflect"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/chriso345/clifford/display"
 	"github.com/chriso345/clifford/errors"
 	"github.com/chriso345/clifford/internal/common"
+	"github.com/chriso345/clifford/internal/options"
 )
 
 var osExit = os.Exit // Mockable for testing
 
+// parseState carries the configuration of a single parse through the
+// recursive subcommand dispatch.
+type parseState struct {
+	cfg          *options.Config
+	opts         []options.Option  // the options cfg was built from, passed on to display
+	pure         bool              // report help/version as errors instead of printing and exiting
+	root         any               // the root command target
+	prefix       string            // dotted path of the subcommand being parsed, with a trailing "."
+	command      string            // space-separated names of the subcommand being parsed
+	set          map[string]bool   // dotted paths of the fields that received a value
+	explicit     map[string]bool   // dotted paths of the fields given on the command line
+	supplied     map[string]bool   // dotted paths of the fields set from any source but a default
+	stdinValues  map[string]string // values read by WithJSONStdin, keyed by long name
+	configValues map[string]string // merged values from WithConfigFiles, keyed by long name
+	warnings     []string          // conversion failures tolerated by WithBestEffort
+	bound        []BoundValue      // values bound so far, reported by ParseResult
+	commands     []any             // the command structs dispatched to, root first
+	finished     bool              // whether help or version output was printed
+}
+
+// newParseState returns the state for a parse configured by opts.
+func newParseState(opts []options.Option) *parseState {
+	return &parseState{cfg: options.New(opts...), opts: opts, set: map[string]bool{}, explicit: map[string]bool{}, supplied: map[string]bool{}}
+}
+
+// finish prints out to the configured writer, stdout by default, and exits the
+// program. In pure mode nothing is printed and sentinel is returned instead.
+// When a WithExitFunc function returns rather than exiting, sentinel is
+// returned after printing.
+func (s *parseState) finish(out string, sentinel error) error {
+	s.finished = true
+	if s.pure {
+		return sentinel
+	}
+	w := s.cfg.Output
+	if w == nil {
+		w = os.Stdout
+	}
+	_ = display.WriteText(w, out, s.opts...)
+	if s.cfg.Exit != nil {
+		s.cfg.Exit(0)
+		return sentinel
+	}
+	osExit(0)
+	return nil
+}
+
+// exit ends the program with code through the configured exit function.
+func (s *parseState) exit(code int) {
+	if s.cfg.Exit != nil {
+		s.cfg.Exit(code)
+		return
+	}
+	osExit(code)
+}
+
+// numericArg matches tokens such as -5, -2.5, -1h30m or -10MB that read as
+// signed numbers, durations or sizes rather than flags.
+var numericArg = regexp.MustCompile(`^-?(\d+(\.\d+)?[a-zA-Zµ]*)+$`)
+
+// isFlagToken reports whether arg is a flag. Negative numbers are values unless
+// a flag with that literal name is declared.
+func isFlagToken(arg string, declared map[string]bool) bool {
+	if !strings.HasPrefix(arg, "-") {
+		return false
+	}
+	return !numericArg.MatchString(arg) || declared[arg]
+}
+
+// hasSubcommandAfter reports whether any of args before a "--" names one of
+// the subcommands in decl.
+func hasSubcommandAfter(args []string, decl declaration) bool {
+	for _, arg := range args {
+		if arg == "--" {
+			return false
+		}
+		if decl.subcommands[arg] {
+			return true
+		}
+	}
+	return false
+}
+
 // buildArgMaps processes the provided args and returns maps for flags and positionals.
-func buildArgMaps(args []string) (map[string]string, map[string]int, []string, []int) {
-	argMap := map[string]string{}
+// Flag values are recorded in order, so repeated flags keep every occurrence.
+// decl describes the command being parsed: switches such as booleans only take
+// an explicit true or false as their value, and a flag only takes one of its
+// subcommand names as a value when a later argument could still be the
+// subcommand, so `app -n serve serve` sets -n while `app -n serve` leaves
+// serve to dispatch.
+func buildArgMaps(args []string, cfg *options.Config, decl declaration) (map[string][]string, map[string]int, []string, []int) {
+	argMap := map[string][]string{}
 	argIndex := map[string]int{}
 	used := map[int]bool{}
+	seen := 0 // positionals seen so far
 
 	for i := 0; i < len(args); i++ {
 		arg := args[i]
-		if strings.HasPrefix(arg, "--") || strings.HasPrefix(arg, "-") {
+		if seen == decl.restAt && !isFlagToken(arg, decl.flags) {
+			// A rest positional takes everything from here on verbatim
+			break
+		}
+		if arg == "--" {
+			// Terminate flag parsing: everything after is positional
+			used[i] = true
+			break
+		}
+		if isPlusFlag(arg, cfg) {
+			// +x toggles never take a value
 			argIndex[arg] = i
 			used[i] = true
-			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
-				argMap[arg] = args[i+1]
+			continue
+		}
+		if flag, n, ok := countRun(arg, decl.counts); ok {
+			// Count flags never take a value; -vvv counts as three
+			argIndex[flag] = i
+			argMap[flag] = append(argMap[flag], strconv.Itoa(n))
+			used[i] = true
+			continue
+		}
+		if isFlagToken(arg, decl.flags) {
+			used[i] = true
+			// --flag=value carries its value inline and never consumes the next token
+			if name, value, ok := strings.Cut(arg, "="); ok {
+				argIndex[name] = i
+				argMap[name] = append(argMap[name], value)
+				continue
+			}
+			argIndex[arg] = i
+			if decl.takesNoValue(arg) && (i+1 >= len(args) || (args[i+1] != "true" && args[i+1] != "false")) {
+				// Switches only consume an explicit true or false. A bare switch
+				// stands for true, so it overrides an earlier explicit value.
+				argMap[arg] = append(argMap[arg], "true")
+				continue
+			}
+			if i+1 < len(args) && !isFlagToken(args[i+1], decl.flags) && !isPlusFlag(args[i+1], cfg) && (!decl.subcommands[args[i+1]] || hasSubcommandAfter(args[i+2:], decl)) {
+				argMap[arg] = append(argMap[arg], args[i+1])
 				used[i+1] = true
 				i++ // skip the value
 			}
+			continue
 		}
+		seen++
 	}
 
 	var positionals []string
@@ -45,14 +177,187 @@ func buildArgMaps(args []string) (map[string]string, map[string]int, []string, [
 	return argMap, argIndex, positionals, positionalIdxs
 }
 
+// splitGlobals moves the global flags declared by decl, with their values, out
+// of args, which follow a subcommand declared by sub. Flags the subcommand
+// declares itself stay with it, as does everything after "--" or captured by
+// its rest positional.
+func splitGlobals(args []string, decl, sub declaration) (globals, rest []string) {
+	seen := 0 // positionals of the subcommand seen so far
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" || (seen == sub.restAt && !isFlagToken(arg, sub.flags)) {
+			return globals, append(rest, args[i:]...)
+		}
+		name, _, inline := strings.Cut(arg, "=")
+		counted := false
+		if flag, _, ok := countRun(arg, decl.counts); ok {
+			name, counted = flag, true
+		}
+		if decl.globals[name] && !sub.flags[name] {
+			globals = append(globals, arg)
+			if inline || counted || i+1 >= len(args) {
+				continue
+			}
+			next := args[i+1]
+			if decl.takesNoValue(name) {
+				if next == "true" || next == "false" {
+					globals = append(globals, next)
+					i++
+				}
+			} else if !isFlagToken(next, decl.flags) {
+				globals = append(globals, next)
+				i++
+			}
+			continue
+		}
+		rest = append(rest, arg)
+		if !isFlagToken(arg, sub.flags) {
+			seen++
+		} else if !inline && !sub.takesNoValue(arg) && i+1 < len(args) && !isFlagToken(args[i+1], sub.flags) {
+			rest = append(rest, args[i+1])
+			i++
+		}
+	}
+	return globals, rest
+}
+
+// lastIndex returns the position of whichever of flags appears last in
+// argIndex, or -1 when none of them was given.
+func lastIndex(argIndex map[string]int, flags ...string) int {
+	last := -1
+	for _, flag := range flags {
+		if i, ok := argIndex[flag]; ok && i > last {
+			last = i
+		}
+	}
+	return last
+}
+
+// countRun reports whether arg gives a count flag, either by name or as a run
+// of its short letter such as -vvv, returning the flag and the count.
+func countRun(arg string, counts map[string]bool) (string, int, bool) {
+	if counts[arg] {
+		return arg, 1, true
+	}
+	if len(arg) < 3 || arg[0] != '-' || arg[1] == '-' {
+		return "", 0, false
+	}
+	flag := arg[:2]
+	if !counts[flag] || strings.Trim(arg[1:], arg[1:2]) != "" {
+		return "", 0, false
+	}
+	return flag, len(arg) - 1, true
+}
+
+// isPlusFlag reports whether arg is a `+x` toggle and such toggles are enabled.
+func isPlusFlag(arg string, cfg *options.Config) bool {
+	return cfg.PlusFlags && len(arg) > 1 && strings.HasPrefix(arg, "+")
+}
+
+// lookup resolves the raw command-line value for b, reporting whether it was
+// supplied. Positional bindings take their value from positionals.
+func (s *parseState) lookup(b binding, argMap map[string][]string, argIndex map[string]int, positionals *positionalQueue) (string, bool) {
+	long := b.tags["long"]
+	longFlag := "--" + long
+	shortFlag := "-" + b.tags["short"]
+
+	// A paired boolean answers to --enable-x/--disable-x in place of its long
+	// flag; whichever appears last wins.
+	if on, off, ok := common.PairFlags(b.tags, b.name); ok && b.isBool() {
+		onIdx, onSet := argIndex[on]
+		offIdx, offSet := argIndex[off]
+		switch {
+		case onSet && (!offSet || onIdx > offIdx):
+			return "true", true
+		case offSet:
+			return "false", true
+		}
+		long = ""
+	}
+
+	// With +x toggles enabled, whichever of -x and +x appears last wins.
+	if s.cfg.PlusFlags && b.tags["short"] != "" && b.isBool() {
+		if plusIdx, ok := argIndex["+"+b.tags["short"]]; ok {
+			if minusIdx, ok := argIndex[shortFlag]; !ok || plusIdx > minusIdx {
+				return "false", true
+			}
+		}
+	}
+
+	// A boolean also answers to --no-<long>; whichever form appears last wins.
+	if neg := common.NegatedFlag(b.tags); neg != "" && b.isBool() {
+		if negIdx, ok := argIndex[neg]; ok && negIdx > lastIndex(argIndex, longFlag, shortFlag) {
+			return "false", true
+		}
+	}
+
+	// A count flag totals every occurrence, so -v -vv gives 3
+	if b.isCount() {
+		values := flagValues(b, argMap)
+		if len(values) == 0 {
+			return "", false
+		}
+		total := 0
+		for _, v := range values {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return v, true // reported as an invalid value on assignment
+			}
+			total += n
+		}
+		return strconv.Itoa(total), true
+	}
+
+	// Check long then short flag values; when repeated, the last one wins
+	if long != "" {
+		if vals, ok := argMap[longFlag]; ok {
+			return vals[len(vals)-1], true
+		}
+	}
+	if b.tags["short"] != "" {
+		if vals, ok := argMap[shortFlag]; ok {
+			return vals[len(vals)-1], true
+		}
+	}
+	// Handle boolean flags (without values)
+	if long != "" {
+		if _, ok := argIndex[longFlag]; ok {
+			return "true", true
+		}
+	}
+	if b.tags["short"] != "" {
+		if _, ok := argIndex[shortFlag]; ok {
+			return "true", true
+		}
+	}
+
+	// Handle positional arguments (no short or long tag)
+	if !b.isFlag() {
+		return positionals.take(b)
+	}
+	return "", false
+}
+
 // parseFields parses flags/positionals into the provided target using only the given args.
 // This function does not perform subcommand dispatching.
-func parseFields(target any, args []string) error {
+func (s *parseState) parseFields(target any, args []string) error {
 	if !common.IsStructPtr(target) {
 		return errors.NewParseError("invalid type: must pass pointer to struct")
 	}
 
-	argMap, argIndex, positionals, _ := buildArgMaps(args)
+	// A rest_required field takes everything after "--" as its command
+	decl := declare(target)
+	var tail []string
+	if i := common.ArgsIndexOf(args, "--"); i >= 0 && decl.tail {
+		args, tail = args[:i], args[i+1:]
+	}
+
+	// Subcommands are dispatched before the fields are parsed, so a value
+	// here that names one belongs to its flag
+	fieldDecl := decl
+	fieldDecl.subcommands = nil
+	argMap, argIndex, positionals, _ := buildArgMaps(args, s.cfg, fieldDecl)
+	debugf("parse %s: flags %v, %d positionals", s.commandName(), givenFlags(argIndex), len(positionals))
 
 	// Determine root help exposure mode (flag/subcmd/both). Default is flag.
 	helpMode := "flag"
@@ -70,381 +375,466 @@ func parseFields(target any, args []string) error {
 			}
 		}
 	}
+	// Under WithRootOnlyHelp, subcommands never handle the help and version flags
+	metaFlags := !s.cfg.RootOnlyHelp || target == s.root
+
 	// Handle --help only when helpMode allows flag-based help
-	if helpMode != "subcmd" && common.MetaArgEnabled("Help", target) {
+	if metaFlags && helpMode != "subcmd" && common.MetaArgEnabled("Help", target) {
 		if _, ok := argIndex["-h"]; ok {
-			help, err := display.BuildHelp(target, false)
+			help, err := display.BuildHelp(target, false, s.opts...)
 			if err != nil {
 				return err
 			}
-			fmt.Println(help)
-			osExit(0)
+			return s.finish(help, errors.ErrHelpRequested)
 		}
 		if _, ok := argIndex["--help"]; ok {
-			help, err := display.BuildHelp(target, true)
+			help, err := display.BuildHelp(target, true, s.opts...)
 			if err != nil {
 				return err
 			}
-			fmt.Println(help)
-			osExit(0)
+			return s.finish(help, errors.ErrHelpRequested)
 		}
 	}
 
-	// Handle --version
-	if common.MetaArgEnabled("Version", target) {
-		if _, ok := argIndex["--version"]; ok {
+	// Handle --version, and -v where it is not taken
+	if metaFlags && common.MetaArgEnabled("Version", target) {
+		_, long := argIndex["--version"]
+		_, short := argIndex["-v"]
+		if long || (short && shortVersionFlag(target)) {
 			version, err := display.BuildVersion(target)
 			if err != nil {
 				return err
 			}
-			fmt.Println(version)
-			osExit(0)
+			return s.finish(version, errors.ErrVersionRequested)
 		}
 	}
 
-	v := reflect.ValueOf(target).Elem()
-	t := v.Type()
-	positionalIndex := 0
-
-	for i := range t.NumField() {
-		field := t.Field(i)
+	// Under WithAllErrors, problems are collected in errs rather than
+	// returned as soon as they are found
+	var errs []error
+	fail := func(err error) bool {
+		errs = append(errs, err)
+		return !s.cfg.AllErrors
+	}
+	for _, err := range s.checkUnknownFlags(target, argIndex, helpMode) {
+		if fail(err) {
+			return err
+		}
+	}
 
-		// Skip meta fields like Clifford, Version, Help and inline Desc or other non-value structs
-		if field.Type.Name() == "Clifford" || field.Type.Name() == "Version" || field.Type.Name() == "Help" {
-			continue
+	bindings := collectBindings(reflect.ValueOf(target).Elem())
+	// An assignment field takes the KEY=VALUE positionals before the others
+	// are handed out
+	var assignments []string
+	for _, b := range bindings {
+		if b.isAssignment() {
+			positionals, assignments = splitAssignments(positionals, b.tags["kv_separator"])
+			break
 		}
-		if field.Type.Kind() != reflect.Struct {
-			// Skip anonymous embedded non-struct markers (like Subcommand)
-			if field.Anonymous {
-				continue
-			}
-			// Handle inline primitive fields (e.g. MaxItems int `short:"n" long:"max-items"`)
-			tags := make(map[string]string)
-			for _, key := range []string{"default", "desc", "required", "short", "long"} {
-				if val := field.Tag.Get(key); val != "" {
-					tags[key] = val
+	}
+	queue, err := newPositionalQueue(positionals, bindings)
+	if err != nil {
+		return err
+	}
+	for _, b := range bindings {
+		// Marker-only fields can never be supplied
+		if !b.value.IsValid() {
+			if b.tags["required"] == "true" {
+				if err := errors.NewMissingArg(b.name); fail(err) {
+					return err
 				}
 			}
+			continue
+		}
 
-			var value string
-			found := false
-
-			longFlag := "--" + tags["long"]
-			shortFlag := "-" + tags["short"]
-
-			// Check long flag
-			if tags["long"] != "" {
-				if val, ok := argMap[longFlag]; ok {
-					value = val
-					found = true
-				}
-			}
-			// Check short flag
-			if !found && tags["short"] != "" {
-				if val, ok := argMap[shortFlag]; ok {
-					value = val
-					found = true
-				}
-			}
-			// Handle boolean flags (without values)
-			if !found && tags["long"] != "" {
-				if _, ok := argIndex[longFlag]; ok {
-					value = "true"
-					found = true
+		if b.isTail() {
+			if len(tail) == 0 {
+				if err := errors.NewMissingArg(b.name); fail(err) {
+					return err
 				}
+				continue
 			}
-			if !found && tags["short"] != "" {
-				if _, ok := argIndex[shortFlag]; ok {
-					value = "true"
-					found = true
+			if err := s.assignEntries(b, tail); err != nil {
+				if fail(err) {
+					return err
 				}
+				continue
 			}
+			s.record(b, strings.Join(tail, " "), sourceArgs)
+			continue
+		}
 
-			// Handle positional arguments (no short or long tag)
-			if !found && tags["short"] == "" && tags["long"] == "" {
-				if positionalIndex < len(positionals) {
-					value = positionals[positionalIndex]
-					positionalIndex++
-					found = true
+		if b.isAssignment() {
+			if len(assignments) == 0 {
+				if b.tags["required"] == "true" {
+					if err := errors.NewMissingArg(b.name); fail(err) {
+						return err
+					}
 				}
+				continue
 			}
-
-			// If not found, use any declared default value.
-			if !found {
-				if d, ok := tags["default"]; ok && d != "" {
-					value = d
-					found = true
+			if err := s.assignEntries(b, assignments); err != nil {
+				if fail(err) {
+					return err
 				}
+				continue
 			}
+			s.record(b, strings.Join(assignments, " "), sourceArgs)
+			continue
+		}
 
-			// Required check
-			if !found && tags["required"] == "true" {
-				return errors.NewMissingArg(field.Name)
+		if flag, ok := valuelessFlag(b, argMap, argIndex); ok {
+			if err := errors.NewParseError(fmt.Sprintf("flag %s requires a value", flag)); fail(err) {
+				return err
 			}
+			continue
+		}
+		value, found := s.lookup(b, argMap, argIndex, queue)
+		source := sourceArgs
 
-			// Set the value directly on the field
-			if found {
-				valField := v.Field(i)
-				if !valField.IsValid() || !valField.CanSet() {
-					continue
-				}
+		// If not given on the command line, fall back to lower-precedence sources.
+		if !found {
+			value, source, found = s.fallback(b)
+		}
+		if found {
+			debugf("bind %s%s = %q from %s", s.prefix, b.path, debugValue(b, value), source)
+		} else {
+			debugf("bind %s%s: no value", s.prefix, b.path)
+		}
 
-				switch valField.Kind() {
-				case reflect.String:
-					valField.SetString(value)
-				case reflect.Int:
-					if intVal, err := strconv.Atoi(value); err == nil {
-						valField.SetInt(int64(intVal))
-					}
-				case reflect.Float64:
-					if floatVal, err := strconv.ParseFloat(value, 64); err == nil {
-						valField.SetFloat(floatVal)
-					}
-				case reflect.Bool:
-					if boolVal, err := strconv.ParseBool(value); err == nil {
-						valField.SetBool(boolVal)
-					}
-				default:
-					return errors.NewUnsupportedField(field.Name, valField.Kind().String())
+		// Required check
+		if !found && b.tags["required"] == "true" {
+			if err := errors.NewMissingArg(b.name); fail(err) {
+				return err
+			}
+			continue
+		}
+		if !found && b.tags["required_if"] != "" {
+			if flag, ok := providedFlag(argIndex, b.tags["required_if"]); ok {
+				if err := errors.NewConditionalMissingArg(b.name, flag); fail(err) {
+					return err
 				}
+				continue
 			}
+		}
 
+		if !found || !b.value.CanSet() {
 			continue
 		}
-
-		// Skip marker-only embedded structs that don't have a Value field
-		if _, ok := field.Type.FieldByName("Value"); !ok {
-			// If the field is marked Required at the struct level (e.g., embedded Required), report missing
-			if common.GetTagsFromEmbedded(field.Type, field.Name)["required"] == "true" {
-				return errors.NewMissingArg(field.Name)
+		// Map and slice fields collect an entry from every occurrence of their flag
+		entries := []string{value}
+		if collects(b.value) {
+			if all := flagValues(b, argMap); len(all) > 0 {
+				entries = all
+			}
+		}
+		if b.isRest() || b.isVariadic() {
+			entries = append(entries, queue.takeRest()...)
+		}
+		if err := s.assignEntries(b, entries); err != nil {
+			if !s.tolerate(b, err) && fail(err) {
+				return err
 			}
 			continue
 		}
+		s.record(b, strings.Join(entries, " "), source)
+	}
 
-		subVal := v.Field(i)
-		subType := field.Type
-		tags := common.GetTagsFromEmbedded(subType, field.Name)
-		// Skip subcommand containers; they are dispatched separately
-		if tags["subcmd"] == "true" {
-			continue
+	// Positionals left over once every field is bound were not expected
+	if extra := queue.takeRest(); len(extra) > 0 {
+		if err := errors.NewTooManyArgs(extra); fail(err) {
+			return err
 		}
+	}
+	if err := s.checkConstraints(bindings); err != nil && fail(err) {
+		return err
+	}
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	if len(errs) > 1 {
+		return errors.NewMultiError(errs)
+	}
 
-		// First, check if the sub-struct itself has a short/long tags (i.e., acts as a flag container)
-		var value string
-		found := false
+	if validate := s.cfg.Validators[s.command]; validate != nil {
+		if err := validate(target); err != nil {
+			return errors.NewValidationError(s.command, err)
+		}
+	}
+	return nil
+}
 
-		longFlag := "--" + tags["long"]
-		shortFlag := "-" + tags["short"]
+// sourceArgs is the source recorded for values given on the command line.
+const sourceArgs = "command line"
 
-		// Check long flag on container
-		if tags["long"] != "" {
-			if val, ok := argMap[longFlag]; ok {
-				value = val
-				found = true
-			}
+// sourceDefault is the source recorded for values taken from a `default` tag.
+const sourceDefault = "default"
+
+// record notes that b received value from source.
+func (s *parseState) record(b binding, value, source string) {
+	path := s.prefix + b.path
+	s.set[path] = true
+	if source != sourceDefault {
+		s.supplied[path] = true
+	}
+	if source == sourceArgs {
+		s.explicit[path] = true
+	}
+	s.bound = append(s.bound, BoundValue{Path: path, Value: debugValue(b, value), Source: source})
+}
+
+// assignEntries normalizes and assigns each raw entry to b in order.
+func (s *parseState) assignEntries(b binding, entries []string) error {
+	for _, entry := range entries {
+		entry, err := fileValue(b, entry)
+		if err != nil {
+			return err
 		}
-		// Check short flag on container
-		if !found && tags["short"] != "" {
-			if val, ok := argMap[shortFlag]; ok {
-				value = val
-				found = true
-			}
+		entry, err = checkChoice(b, s.normalize(b, entry))
+		if err != nil {
+			return err
 		}
-		// Handle boolean flags (without values) on container
-		if !found && tags["long"] != "" {
-			if _, ok := argIndex[longFlag]; ok {
-				value = "true"
-				found = true
-			}
+		if err := s.assign(b, entry); err != nil {
+			return err
 		}
-		if !found && tags["short"] != "" {
-			if _, ok := argIndex[shortFlag]; ok {
-				value = "true"
-				found = true
-			}
+		if err := checkRange(b); err != nil {
+			return err
+		}
+		if err := checkPattern(b); err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		// Handle positional arguments for the container (no short or long tag)
-		if !found && tags["short"] == "" && tags["long"] == "" {
-			if positionalIndex < len(positionals) {
-				value = positionals[positionalIndex]
-				positionalIndex++
-				found = true
-			}
+// checkChoice returns an InvalidChoiceError when b declares `choices` and
+// value is not one of them. With `choices_ci:"true"` the comparison ignores
+// case and value is returned in the declared spelling.
+func checkChoice(b binding, value string) (string, error) {
+	choices := common.ParseChoices(b.tags["choices"])
+	if len(choices) == 0 || b.value.Kind() == reflect.Map {
+		return value, nil
+	}
+	allowed := make([]string, len(choices))
+	for i, c := range choices {
+		if c.Value == value || (b.tags["choices_ci"] == "true" && strings.EqualFold(c.Value, value)) {
+			return c.Value, nil
 		}
+		allowed[i] = c.Value
+	}
+	return "", errors.NewInvalidChoice(b.name, value, allowed)
+}
 
-		// If not found, use any declared default value on container.
-		if !found {
-			if d, ok := tags["default"]; ok && d != "" {
-				value = d
-				found = true
-			}
+// tolerate handles err from assigning b under WithBestEffort, reporting
+// whether parsing may continue. A value that fails to convert is recorded as
+// a warning and b is reset to its default, or its zero value when it has no
+// usable default.
+func (s *parseState) tolerate(b binding, err error) bool {
+	invalid := stderrors.As(err, new(errors.InvalidValueError)) || stderrors.As(err, new(errors.InvalidChoiceError)) ||
+		stderrors.As(err, new(errors.RangeError)) || stderrors.As(err, new(errors.PatternError))
+	if !s.cfg.BestEffort || !invalid {
+		return false
+	}
+	s.warnings = append(s.warnings, err.Error())
+	b.value.Set(reflect.Zero(b.value.Type()))
+	if d := b.tags["default"]; d != "" {
+		if s.assignEntries(b, []string{d}) == nil {
+			s.set[s.prefix+b.path] = true
+		} else {
+			b.value.Set(reflect.Zero(b.value.Type()))
 		}
+	}
+	return true
+}
 
-		// Required check for container
-		if !found && tags["required"] == "true" {
-			return errors.NewMissingArg(field.Name)
+// shortVersionFlag reports whether -v prints the version of target, as it
+// does unless target declares a -v flag of its own or its Clifford embedding
+// is tagged `version_short:"false"`.
+func shortVersionFlag(target any) bool {
+	if declare(target).flags["-v"] {
+		return false
+	}
+	if field, ok := common.GetStructType(target).FieldByName("Clifford"); ok && field.Tag.Get("version_short") == "false" {
+		return false
+	}
+	return true
+}
+
+// checkUnknownFlags returns an UnknownFlagError for each flag in argIndex that
+// target does not declare, in command-line order. The help and version flags
+// count as declared wherever they are enabled. The check is skipped when the
+// root Clifford embedding is tagged `allow_unknown:"true"`.
+func (s *parseState) checkUnknownFlags(target any, argIndex map[string]int, helpMode string) []error {
+	if root, ok := common.GetStructType(s.root).FieldByName("Clifford"); ok && root.Tag.Get("allow_unknown") == "true" {
+		return nil
+	}
+	known := declare(target).flags
+	if helpMode != "subcmd" && common.MetaArgEnabled("Help", target) {
+		known["-h"], known["--help"] = true, true
+	}
+	if common.MetaArgEnabled("Version", target) {
+		known["--version"] = true
+		if shortVersionFlag(target) {
+			known["-v"] = true
 		}
+	}
 
-		// If a value was found for the container, set it to its Value field
-		if found {
-			valField := subVal.FieldByName("Value")
-			if valField.IsValid() && valField.CanSet() {
-				switch valField.Kind() {
-				case reflect.String:
-					valField.SetString(value)
-				case reflect.Int:
-					if intVal, err := strconv.Atoi(value); err == nil {
-						valField.SetInt(int64(intVal))
-					}
-				case reflect.Float64:
-					if floatVal, err := strconv.ParseFloat(value, 64); err == nil {
-						valField.SetFloat(floatVal)
-					}
-				case reflect.Bool:
-					if boolVal, err := strconv.ParseBool(value); err == nil {
-						valField.SetBool(boolVal)
-					}
-				default:
-					return errors.NewUnsupportedField(field.Name, valField.Kind().String())
-				}
-			}
+	var unknown []string
+	for flag := range argIndex {
+		name := flag
+		if isPlusFlag(flag, s.cfg) {
+			name = "-" + flag[1:] // +x toggles stand in for their -x flag
+		}
+		if !known[name] {
+			unknown = append(unknown, flag)
 		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Slice(unknown, func(i, j int) bool { return argIndex[unknown[i]] < argIndex[unknown[j]] })
+	candidates := make([]string, 0, len(known))
+	for flag := range known {
+		candidates = append(candidates, flag)
+	}
+	sort.Strings(candidates)
+	errs := make([]error, len(unknown))
+	for i, flag := range unknown {
+		errs[i] = errors.NewUnknownFlag(flag, closestMatch(flag, candidates))
+	}
+	return errs
+}
 
-		// Next, handle inline primitive fields declared inside the sub-struct (e.g., MaxItems int `short:"n" long:"max-items"`)
-		for j := 0; j < subType.NumField(); j++ {
-			inner := subType.Field(j)
-			// skip anonymous embedded markers
-			if inner.Anonymous {
-				continue
-			}
-			// Skip inner Value field (handled by container) and only consider non-struct primitive fields
-			if inner.Name == "Value" {
-				continue
+// checkConstraints enforces the `requires` and `conflicts` tags of the
+// bindings given on the command line. A flag fails when a flag it requires
+// was not supplied on the command line, in the environment, on stdin or in a
+// config file, as a default does not count, or when a flag it conflicts with
+// was also given on the command line.
+func (s *parseState) checkConstraints(bindings []binding) error {
+	for _, b := range bindings {
+		if !s.explicit[s.prefix+b.path] {
+			continue
+		}
+		for _, name := range tagList(b.tags["requires"]) {
+			if other, ok := resolveBinding(bindings, name); ok && !s.supplied[s.prefix+other.path] {
+				return errors.NewFlagConstraint(flagName(b), flagName(other), false)
 			}
-			if inner.Type.Kind() == reflect.Struct {
-				continue
+		}
+		for _, name := range tagList(b.tags["conflicts"]) {
+			if other, ok := resolveBinding(bindings, name); ok && s.explicit[s.prefix+other.path] {
+				return errors.NewFlagConstraint(flagName(b), flagName(other), true)
 			}
+		}
+	}
+	return nil
+}
 
-			// Collect tags from struct tags on the inner field
-			tags2 := make(map[string]string)
-			for _, key := range []string{"default", "desc", "required", "short", "long"} {
-				if val := inner.Tag.Get(key); val != "" {
-					tags2[key] = val
-				}
-			}
-			// allow positional inner fields (no short/long) as well
-			// proceed even if short/long empty
-
-			lFlag := "--" + tags2["long"]
-			sFlag := "-" + tags2["short"]
-			var iv string
-			foundInner := false
-			if tags2["long"] != "" {
-				if val, ok := argMap[lFlag]; ok {
-					iv = val
-					foundInner = true
-				}
-			}
-			if !foundInner && tags2["short"] != "" {
-				if val, ok := argMap[sFlag]; ok {
-					iv = val
-					foundInner = true
-				}
-			}
-			if !foundInner && tags2["long"] != "" {
-				if _, ok := argIndex[lFlag]; ok {
-					iv = "true"
-					foundInner = true
-				}
-			}
-			if !foundInner && tags2["short"] != "" {
-				if _, ok := argIndex[sFlag]; ok {
-					iv = "true"
-					foundInner = true
-				}
-			}
-			if !foundInner && tags2["short"] == "" && tags2["long"] == "" {
-				// positional inner field
-				if positionalIndex < len(positionals) {
-					iv = positionals[positionalIndex]
-					positionalIndex++
-					foundInner = true
-				}
-			}
-			if !foundInner {
-				if d, ok := tags2["default"]; ok && d != "" {
-					iv = d
-					foundInner = true
-				}
-			}
-			if !foundInner && tags2["required"] == "true" {
-				return errors.NewMissingArg(inner.Name)
-			}
-			if foundInner {
-				// set value on subVal's field
-				f := subVal.FieldByName(inner.Name)
-				if !f.IsValid() || !f.CanSet() {
-					continue
-				}
-				switch f.Kind() {
-				case reflect.String:
-					f.SetString(iv)
-				case reflect.Int:
-					if intVal, err := strconv.Atoi(iv); err == nil {
-						f.SetInt(int64(intVal))
-					}
-				case reflect.Float64:
-					if floatVal, err := strconv.ParseFloat(iv, 64); err == nil {
-						f.SetFloat(floatVal)
-					}
-				case reflect.Bool:
-					if boolVal, err := strconv.ParseBool(iv); err == nil {
-						f.SetBool(boolVal)
-					}
-				default:
-					return errors.NewUnsupportedField(inner.Name, f.Kind().String())
-				}
-			}
+// tagList splits a comma-separated tag value into its trimmed, non-empty
+// entries.
+func tagList(tag string) []string {
+	var entries []string
+	for _, entry := range strings.Split(tag, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
 		}
 	}
+	return entries
+}
 
-	return nil
+// resolveBinding finds the binding name refers to: a field name or dotted
+// path, a long flag name, or a single-letter short flag name.
+func resolveBinding(bindings []binding, name string) (binding, bool) {
+	for _, b := range bindings {
+		if b.name == name || b.path == name || b.tags["long"] == name || (len(name) == 1 && b.tags["short"] == name) {
+			return b, true
+		}
+	}
+	return binding{}, false
+}
+
+// flagName renders b as it is given on the command line, such as "--tls-key",
+// falling back to its field name for positionals.
+func flagName(b binding) string {
+	if long := b.tags["long"]; long != "" {
+		return "--" + long
+	}
+	if short := b.tags["short"]; short != "" {
+		return "-" + short
+	}
+	return b.name
+}
+
+// providedFlag reports whether the flag with the given name was passed on the
+// command line, returning it in the form it was given. Single-letter names
+// match short flags, longer names match long flags.
+func providedFlag(argIndex map[string]int, name string) (string, bool) {
+	flag := "--" + name
+	if len(name) == 1 {
+		flag = "-" + name
+	}
+	_, ok := argIndex[flag]
+	return flag, ok
+}
+
+// valuelessFlag reports a flag of b that was given on the command line
+// without a value, such as a trailing `--port`. Switches never need one.
+func valuelessFlag(b binding, argMap map[string][]string, argIndex map[string]int) (string, bool) {
+	if b.isSwitch() {
+		return "", false
+	}
+	var flags []string
+	if short := b.tags["short"]; short != "" {
+		flags = append(flags, "-"+short)
+	}
+	if long := b.tags["long"]; long != "" {
+		flags = append(flags, "--"+long)
+	}
+	for _, flag := range flags {
+		if _, given := argIndex[flag]; given && len(argMap[flag]) == 0 {
+			return flag, true
+		}
+	}
+	return "", false
 }
 
 // parseWithArgs is the recursive parser that supports subcommand dispatch.
-func parseWithArgs(target any, args []string) error {
+func (s *parseState) parseWithArgs(target any, args []string) error {
 	if !common.IsStructPtr(target) {
 		return errors.NewParseError("invalid type: must pass pointer to struct")
 	}
 
-	// Normalize args: drop everything before "--"
-	if i := common.ArgsIndexOf(args, "--"); i >= 0 {
-		args = args[i+1:]
-	}
+	s.commands = append(s.commands, target)
 
 	// Build maps for full args to discover subcommands
-	_, _, positionals, positionalIdxs := buildArgMaps(args)
+	_, _, positionals, positionalIdxs := buildArgMaps(args, s.cfg, declare(target))
 
 	// If there's a potential subcommand (first positional), attempt to match it.
 	if len(positionals) > 0 {
 		first := positionals[0]
 		v := reflect.ValueOf(target).Elem()
 		t := v.Type()
+		// Support invocation form: app version, unless it would shadow a
+		// user-defined subcommand or positional argument
+		if first == "version" && len(positionals) == 1 && common.MetaArgEnabled("Version", target) && !declaresCommandOrPositional(v, "version") {
+			version, err := display.BuildVersion(target)
+			if err != nil {
+				return err
+			}
+			return s.finish(version, errors.ErrVersionRequested)
+		}
 		// Support invocation form: app help [subcommand]
 		if first == "help" {
 			if len(positionals) == 1 {
-				helper, err := display.BuildHelp(target, false)
+				helper, err := display.BuildHelp(target, true, s.opts...)
 				if err != nil {
 					return err
 				}
-				fmt.Println(helper)
 				// Always exit after printing help
-				osExit(0)
+				return s.finish(helper, errors.ErrHelpRequested)
 			}
 			second := positionals[1]
-			// collect subcommand names for suggestion
-			var subNames []string
+			// collect subcommand names and aliases for suggestion
+			var subNames, aliases []string
 			for i := range t.NumField() {
 				field := t.Field(i)
 				if field.Type.Kind() != reflect.Struct {
@@ -458,28 +848,30 @@ func parseWithArgs(target any, args []string) error {
 				if name == "" {
 					name = strings.ToLower(field.Name)
 				}
-				subNames = append(subNames, name)
-				if name == second {
+				// Hidden subcommands are accepted but never suggested
+				if tags["hidden"] != "true" {
+					subNames = append(subNames, name)
+					aliases = append(aliases, common.SubcommandAliases(tags)...)
+				}
+				if common.IsSubcommandName(tags, field.Name, second) {
 					// Only allow help via subcommand when the subcommand advertises help as subcmd or both
 					if ht := tags["help"]; ht == "subcmd" || ht == "both" {
 						subPtr := v.Field(i).Addr().Interface()
-						helper, err := display.BuildHelpWithParent(target, name, subPtr, false)
+						helper, err := display.BuildHelpWithParent(s.root, s.commandPath(name), subPtr, false, s.opts...)
 						if err != nil {
 							return err
 						}
-						fmt.Println(helper)
 						// Always exit after printing help
-						osExit(0)
+						return s.finish(helper, errors.ErrHelpRequested)
 					}
 				}
 			}
 			// No matching subcommand found: return informative error
 			if len(subNames) > 0 {
-				suggestion := closestMatch(second, subNames)
-				return errors.NewUnknownSubcommand(second, suggestion)
+				return s.unknownSubcommand(second, subNames, aliases)
 			}
 		}
-		var subNames []string
+		var subNames, aliases []string
 		for i := range t.NumField() {
 			field := t.Field(i)
 			if field.Type.Kind() != reflect.Struct {
@@ -494,12 +886,19 @@ func parseWithArgs(target any, args []string) error {
 			if name == "" {
 				name = strings.ToLower(field.Name)
 			}
-			subNames = append(subNames, name)
-			if name == first {
-				// Parse root fields with only args before the subcommand token
+			// Hidden subcommands are accepted but never suggested
+			if tags["hidden"] != "true" {
+				subNames = append(subNames, name)
+				aliases = append(aliases, common.SubcommandAliases(tags)...)
+			}
+			if common.IsSubcommandName(tags, field.Name, first) {
+				// Parse root fields with only args before the subcommand token,
+				// plus any global flags given after it
 				posIdx := positionalIdxs[0]
-				rootArgs := args[:posIdx]
-				if err := parseFields(target, rootArgs); err != nil {
+				subPtr := v.Field(i).Addr().Interface()
+				globals, subArgs := splitGlobals(args[posIdx+1:], declare(target), declare(subPtr))
+				rootArgs := append(args[:posIdx:posIdx], globals...)
+				if err := s.parseFields(target, rootArgs); err != nil {
 					return err
 				}
 				// Mark the embedded Subcommand boolean field as used (true) so callers can inspect the parsed struct.
@@ -516,11 +915,9 @@ func parseWithArgs(target any, args []string) error {
 					}
 				}
 				// If the subcommand help/version is being requested, build help that shows parent + subcommand.
-				subPtr := v.Field(i).Addr().Interface()
-				subArgs := args[posIdx+1:]
 				// Support positional form: app <subcmd> help
 				if len(subArgs) > 0 && subArgs[0] == "help" {
-					helper, err := display.BuildHelpWithParent(target, name, subPtr, false)
+					helper, err := display.BuildHelpWithParent(s.root, s.commandPath(name), subPtr, true, s.opts...)
 					if err != nil {
 						return err
 					}
@@ -529,8 +926,8 @@ func parseWithArgs(target any, args []string) error {
 					if len(parts) > 0 {
 						usage := parts[0]
 						parentName := ""
-						if common.IsStructPtr(target) {
-							pt := common.GetStructType(target)
+						if common.IsStructPtr(s.root) {
+							pt := common.GetStructType(s.root)
 							for i := range pt.NumField() {
 								f := pt.Field(i)
 								if f.Type.Name() == "Clifford" {
@@ -542,7 +939,7 @@ func parseWithArgs(target any, args []string) error {
 						if parentName == "" {
 							parentName = filepath.Base(os.Args[0])
 						}
-						usage = strings.Replace(usage, parentName+" "+name, parentName, 1)
+						usage = strings.Replace(usage, parentName+" "+s.commandPath(name), strings.TrimSpace(parentName+" "+s.command), 1)
 						if len(parts) == 1 {
 							helper = usage
 						} else {
@@ -565,11 +962,11 @@ func parseWithArgs(target any, args []string) error {
 							continue
 						}
 						sTags := common.GetTagsFromEmbedded(f.Type, f.Name)
-						if sTags["short"] != "" || sTags["long"] != "" {
+						if common.IsFlag(sTags) {
 							continue
 						}
 						nameUp := strings.ToUpper(f.Name)
-						desc := sTags["desc"]
+						desc := s.cfg.Translate("desc."+f.Name, sTags["desc"])
 						req := sTags["required"] == "true"
 						entries = append(entries, struct {
 							name, desc string
@@ -602,28 +999,30 @@ func parseWithArgs(target any, args []string) error {
 							padding := strings.Repeat(" ", paddingCount)
 							b.WriteString(fmt.Sprintf("  %s%s %s\n", left, padding, e.desc))
 						}
-						helper = helper + "\nArguments:\n" + b.String() + "\n"
+						helper = helper + "\n" + s.cfg.Translate("help.arguments", "Arguments") + ":\n" + b.String() + "\n"
 					}
-					fmt.Println(helper)
 					// Always exit after printing help
-					osExit(0)
+					return s.finish(helper, errors.ErrHelpRequested)
 				}
 				for _, a := range subArgs {
+					// A nested subcommand handles the help flags that follow it
+					if a == "--" || s.cfg.RootOnlyHelp || hasSubcommand(subType, a) {
+						break
+					}
 					if a == "-h" || a == "--help" {
 						// Check if the subcommand struct explicitly enables help as a flag
 						subTags := common.GetTagsFromEmbedded(subType, field.Name)
 						if ht := subTags["help"]; ht == "flag" || ht == "both" {
-							helper, err := display.BuildHelpWithParent(target, name, subPtr, a == "--help")
+							helper, err := display.BuildHelpWithParent(s.root, s.commandPath(name), subPtr, a == "--help", s.opts...)
 							if err != nil {
 								return err
 							}
-							fmt.Println(helper)
-							osExit(0)
+							return s.finish(helper, errors.ErrHelpRequested)
 						}
 						// Otherwise, consult root Help embedding: only allow flag-style help if root help mode is not "subcmd".
-						if common.MetaArgEnabled("Help", target) {
+						if common.MetaArgEnabled("Help", s.root) {
 							helpMode := "flag"
-							pt := common.GetStructType(target)
+							pt := common.GetStructType(s.root)
 							for i := range pt.NumField() {
 								f := pt.Field(i)
 								if f.Type.Name() == "Help" {
@@ -636,30 +1035,130 @@ func parseWithArgs(target any, args []string) error {
 								}
 							}
 							if helpMode != "subcmd" {
-								helper, err := display.BuildHelpWithParent(target, name, subPtr, a == "--help")
+								helper, err := display.BuildHelpWithParent(s.root, s.commandPath(name), subPtr, a == "--help", s.opts...)
 								if err != nil {
 									return err
 								}
-								fmt.Println(helper)
-								osExit(0)
+								return s.finish(helper, errors.ErrHelpRequested)
 							}
 						}
 						// If we get here, help isn't enabled in this context; treat as unknown flag
-						return errors.NewParseError("unknown flag: " + a)
+						return errors.NewUnknownFlag(a, "")
 					}
 				}
-				return parseWithArgs(subPtr, subArgs)
+				s.set[s.prefix+field.Name] = true
+				s.explicit[s.prefix+field.Name] = true
+				s.supplied[s.prefix+field.Name] = true
+				s.prefix += field.Name + "."
+				s.command = s.commandPath(name)
+				debugf("dispatch to subcommand %q", s.command)
+				return s.parseWithArgs(subPtr, subArgs)
 			}
 		}
 		// If we had positionals and potential subcommands but no match, return an informative error
 		if len(subNames) > 0 {
-			suggestion := closestMatch(first, subNames)
-			return errors.NewUnknownSubcommand(first, suggestion)
+			return s.unknownSubcommand(first, subNames, aliases)
 		}
 	}
 
 	// No subcommand matched: parse all fields for this target
-	return parseFields(target, args)
+	if err := s.parseFields(target, args); err != nil {
+		return err
+	}
+	if t := common.GetStructType(target); requiresSubcommand(t) {
+		return errors.NewSubcommandRequired(s.command, subcommandNames(t))
+	}
+	return nil
+}
+
+// commandPath returns the space-separated path of the subcommand name below
+// the command being parsed, such as "remote add".
+func (s *parseState) commandPath(name string) string {
+	return strings.TrimSpace(s.command + " " + name)
+}
+
+// commandName names the command being parsed for diagnostics.
+func (s *parseState) commandName() string {
+	if s.command == "" {
+		return "root command"
+	}
+	return fmt.Sprintf("subcommand %q", s.command)
+}
+
+// unknownSubcommand builds the error for an unmatched subcommand name, suggesting
+// the closest of names and aliases and, when configured, listing the names.
+func (s *parseState) unknownSubcommand(name string, names, aliases []string) error {
+	candidates := append(append([]string{}, names...), aliases...)
+	err := errors.UnknownSubcommandError{Name: name, Suggestion: closestMatch(name, candidates)}
+	if s.cfg.ListCommands {
+		err.Available = names
+	}
+	return err
+}
+
+// declaresCommandOrPositional reports whether the command struct v declares a
+// subcommand called name or any positional argument that could receive it.
+func declaresCommandOrPositional(v reflect.Value, name string) bool {
+	if hasSubcommand(v.Type(), name) {
+		return true
+	}
+	for _, b := range collectBindings(v) {
+		if !b.isFlag() {
+			return true
+		}
+	}
+	return false
+}
+
+// subcommandNames returns the names of the visible subcommands declared by
+// the command struct t, in declaration order.
+func subcommandNames(t reflect.Type) []string {
+	var names []string
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if field.Type.Kind() != reflect.Struct {
+			continue
+		}
+		if tags := common.GetTagsFromEmbedded(field.Type, field.Name); tags["subcmd"] == "true" && tags["hidden"] != "true" {
+			names = append(names, common.SubcommandName(tags, field.Name))
+		}
+	}
+	return names
+}
+
+// requiresSubcommand reports whether the command struct t is tagged
+// `require_subcmd:"true"` on its Clifford or Subcommand embedding.
+func requiresSubcommand(t reflect.Type) bool {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if field.Anonymous && (field.Type.Name() == "Clifford" || field.Type.Name() == "Subcommand") && field.Tag.Get("require_subcmd") == "true" {
+			return true
+		}
+	}
+	return false
+}
+
+// hasSubcommand reports whether the command struct t declares a subcommand
+// called name.
+func hasSubcommand(t reflect.Type, name string) bool {
+	_, ok := subcommandType(t, name)
+	return ok
+}
+
+// subcommandType returns the struct type of the subcommand called name, or
+// aliased as name, declared by the command struct t.
+func subcommandType(t reflect.Type, name string) (reflect.Type, bool) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if field.Type.Kind() != reflect.Struct {
+			continue
+		}
+		tags := common.GetTagsFromEmbedded(field.Type, field.Name)
+		if tags["subcmd"] == "true" && common.IsSubcommandName(tags, field.Name, name) {
+			return field.Type, true
+		}
+	}
+	return nil, false
 }
 
 // closestMatch returns the candidate with the smallest edit distance to target, or
@@ -778,6 +1277,136 @@ func levenshtein(a, b string) int {
 	return prev[lb]
 }
 
-func Parse(target any) error {
-	return parseWithArgs(target, os.Args[1:])
+// Parse parses os.Args into target, applying any provided options.
+func Parse(target any, opts ...options.Option) error {
+	return New(opts...).Parse(target)
+}
+
+// ParseArgs parses args, which exclude the program name, into target exactly
+// like Parse does with os.Args.
+func ParseArgs(target any, args []string, opts ...options.Option) error {
+	p := New(opts...)
+	p.opts = append(p.opts, options.WithArgs(args))
+	return p.Parse(target)
+}
+
+// args returns the arguments to parse: those given by WithArgs, or os.Args
+// without the program name.
+func (s *parseState) args() []string {
+	if s.cfg.Args != nil {
+		return s.cfg.Args
+	}
+	return os.Args[1:]
+}
+
+// ParseResult parses os.Args into target like Parse and returns a Result
+// describing the parse.
+func ParseResult(target any, opts ...options.Option) (*Result, error) {
+	s := newParseState(opts)
+	start := time.Now()
+	if err := parse(target, s.args(), s); err != nil {
+		return nil, err
+	}
+	report := Report{Command: s.command, Bound: s.bound, Warnings: s.warnings, Duration: time.Since(start)}
+	return &Result{target: reflect.ValueOf(target).Elem(), set: s.set, explicit: s.explicit, Warnings: s.warnings, report: report}, nil
+}
+
+// ParseInto parses os.Args into a freshly allocated value of target's type and
+// returns a pointer to it. The target itself is only used as a definition and
+// is never modified, so it can be shared safely between goroutines.
+func ParseInto(target any, opts ...options.Option) (any, error) {
+	if !common.IsStructPtr(target) {
+		return nil, errors.NewParseError("invalid type: must pass pointer to struct")
+	}
+	fresh := reflect.New(common.GetStructType(target)).Interface()
+	s := newParseState(opts)
+	if err := parse(fresh, s.args(), s); err != nil {
+		return nil, err
+	}
+	return fresh, nil
+}
+
+// ParseIntoNew parses args into a freshly allocated value of structType and
+// returns a pointer to it. It is a pure variant of ParseInto intended for
+// fuzzing and benchmarks: args are used as given rather than read from os.Args,
+// nothing is written to stdout and the program never exits. Requests for help
+// or version output are reported as errors.ErrHelpRequested and
+// errors.ErrVersionRequested.
+func ParseIntoNew(structType reflect.Type, args []string) (any, error) {
+	if structType == nil || structType.Kind() != reflect.Struct {
+		return nil, errors.NewParseError("invalid type: must pass struct type")
+	}
+	fresh := reflect.New(structType).Interface()
+	if err := parse(fresh, args, &parseState{cfg: options.New(), pure: true, set: map[string]bool{}, explicit: map[string]bool{}, supplied: map[string]bool{}}); err != nil {
+		return nil, err
+	}
+	return fresh, nil
+}
+
+// parse runs a complete parse of args into target using the state s.
+func parse(target any, args []string, s *parseState) error {
+	cfg := s.cfg
+	// A root without a Clifford embedding is almost certainly a mistake
+	if common.IsStructPtr(target) && !common.HasCliffordField(common.GetStructType(target)) {
+		return translate(cfg, errors.NewDefinitionError("root struct must embed clifford.Clifford"))
+	}
+	s.root = target
+	if cfg.FlagsFile && common.IsStructPtr(target) {
+		expanded, err := expandFlagsFile(target, args)
+		if err != nil {
+			return translate(cfg, err)
+		}
+		args = expanded
+	}
+	err := s.loadSources()
+	if err == nil {
+		err = s.parseWithArgs(target, args)
+	}
+	err = translate(cfg, err)
+	if s.usageOnMissing(err) {
+		w := s.errOutput()
+		_ = display.WriteHelp(w, target, false, s.opts...)
+		_ = display.WriteText(w, err.Error(), s.opts...)
+		s.exit(2)
+		return err
+	}
+	if err != nil && cfg.HelpOnError && !cfg.QuietErrors {
+		_ = display.WriteHelp(s.errOutput(), target, false, s.opts...)
+	}
+	return err
+}
+
+// errOutput returns the writer that receives help and errors printed for a
+// failed parse, stderr by default.
+func (s *parseState) errOutput() io.Writer {
+	if s.cfg.ErrOutput != nil {
+		return s.cfg.ErrOutput
+	}
+	return os.Stderr
+}
+
+// usageOnMissing reports whether err is a missing argument or subcommand at the
+// root command that WithUsageOnMissing should answer with help and an exit.
+func (s *parseState) usageOnMissing(err error) bool {
+	if !s.cfg.UsageOnMissing || s.cfg.QuietErrors || s.pure || s.command != "" {
+		return false
+	}
+	return stderrors.As(err, new(errors.MissingArgError)) || stderrors.Is(err, errors.ErrSubcommandRequired)
+}
+
+// translate localizes the message of err through the configured translator,
+// keeping the original error reachable via errors.As. The errors of a
+// MultiError are translated one by one.
+func translate(cfg *options.Config, err error) error {
+	if err == nil || cfg.Translator == nil {
+		return err
+	}
+	if multi, ok := err.(errors.MultiError); ok {
+		errs := make([]error, len(multi.Errors))
+		for i, e := range multi.Errors {
+			errs[i] = translate(cfg, e)
+		}
+		return errors.NewMultiError(errs)
+	}
+	return errors.NewTranslatedError(err, cfg.Translate(errors.Key(err), err.Error()))
 }
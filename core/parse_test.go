@@ -1,12 +1,18 @@
 package core
 
 import (
+	"bytes"
 	stderrs "errors"
+	"fmt"
+	"net"
 	"os"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	clierr "github.com/chriso345/clifford/errors"
+	"github.com/chriso345/clifford/internal/options"
 	"github.com/chriso345/gore/assert"
 )
 
@@ -67,7 +73,7 @@ func TestParse_DebugPositionalArgs(t *testing.T) {
 	oldArgs := os.Args
 	defer func() { os.Args = oldArgs }()
 
-	os.Args = []string{"junk", "junk", "--", "Alice", "30"}
+	os.Args = []string{"mytool", "--", "Alice", "30"}
 
 	cli := struct {
 		Clifford `name:"mytool"`
@@ -123,7 +129,7 @@ func TestParse_UnsupportedFieldType(t *testing.T) {
 	target := struct {
 		Clifford `name:"myapp"`
 		Opt      struct {
-			Value    []string
+			Value    complex128
 			Clifford `long:"opt"`
 		}
 	}{}
@@ -134,7 +140,7 @@ func TestParse_UnsupportedFieldType(t *testing.T) {
 	ok := stderrs.As(err, &ue)
 	assert.True(t, ok)
 	assert.Equal(t, ue.Field, "Opt")
-	assert.StringContains(t, err.Error(), "slice")
+	assert.StringContains(t, err.Error(), "complex128")
 }
 
 func TestParse_InvalidTarget(t *testing.T) {
@@ -207,6 +213,45 @@ func TestParse_VersionFlag(t *testing.T) {
 	t.Errorf("should have exited before this line")
 }
 
+func TestParse_ShortVersionFlag(t *testing.T) {
+	type cli struct {
+		Clifford `name:"mytool"`
+		Version  `version:"1.2.3"`
+	}
+	run := func(target any, args ...string) (string, error) {
+		var buf bytes.Buffer
+		err := New(options.WithArgs(args), options.WithOutput(&buf), options.WithExitFunc(func(int) {})).Parse(target)
+		return buf.String(), err
+	}
+
+	// -v prints the version, as the help text advertises
+	out, err := run(&cli{}, "-v")
+	assert.True(t, stderrs.Is(err, clierr.ErrVersionRequested))
+	assert.True(t, strings.Contains(out, "1.2.3"))
+
+	// A command's own -v flag takes precedence
+	type verbose struct {
+		Clifford `name:"mytool"`
+		Version  `version:"1.2.3"`
+		Verbose  bool `short:"v"`
+	}
+	c := verbose{}
+	out, err = run(&c, "-v")
+	assert.Nil(t, err)
+	assert.True(t, c.Verbose)
+	assert.Equal(t, out, "")
+
+	// With version_short disabled, -v is an unknown flag
+	type long struct {
+		Clifford `name:"mytool" version_short:"false"`
+		Version  `version:"1.2.3"`
+	}
+	_, err = run(&long{}, "-v")
+	var ue clierr.UnknownFlagError
+	assert.True(t, stderrs.As(err, &ue))
+	assert.Equal(t, ue.Name, "-v")
+}
+
 func TestParse_UnknownSubcommand(t *testing.T) {
 	oldArgs := os.Args
 	defer func() { os.Args = oldArgs }()
@@ -290,6 +335,30 @@ func TestSubcommandHelpCallsExit(t *testing.T) {
 
 }
 
+func TestSubcommandHelp_NestedUsage(t *testing.T) {
+	type cli struct {
+		Clifford `name:"app"`
+		Help
+
+		Remote struct {
+			Subcommand
+			Add struct {
+				Subcommand
+				URL struct {
+					Value    string
+					Clifford `long:"url"`
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	err := ParseArgs(&cli{}, []string{"remote", "add", "--help"},
+		options.WithOutput(&buf), options.WithExitFunc(func(int) {}))
+	assert.True(t, stderrs.Is(err, clierr.ErrHelpRequested))
+	assert.True(t, strings.HasPrefix(buf.String(), "Usage: app remote add [OPTIONS]"))
+}
+
 func TestPositionalSubcommandHelpExits(t *testing.T) {
 	oldArgs := os.Args
 	defer func() { os.Args = oldArgs }()
@@ -346,3 +415,1985 @@ func TestPositionalSubcommandHelpExits(t *testing.T) {
 
 	_ = Parse(&target)
 }
+
+func TestParseInto_LeavesTargetUntouched(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	os.Args = []string{"cmd", "--name", "Alice"}
+
+	type cli struct {
+		Clifford `name:"mytool"`
+
+		Name struct {
+			Value    string
+			Clifford `long:"name"`
+		}
+	}
+	definition := cli{}
+
+	parsed, err := ParseInto(&definition)
+	assert.Nil(t, err)
+	copied, ok := parsed.(*cli)
+	assert.True(t, ok)
+	assert.Equal(t, copied.Name.Value, "Alice")
+	assert.Equal(t, definition.Name.Value, "")
+}
+
+func TestParse_MissingCliffordEmbedding(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	os.Args = []string{"cmd", "Alice"}
+
+	cli := struct {
+		Name struct {
+			Value string
+		}
+	}{}
+
+	err := Parse(&cli)
+	assert.NotNil(t, err)
+	var de clierr.DefinitionError
+	ok := stderrs.As(err, &de)
+	assert.True(t, ok)
+	assert.True(t, stderrs.Is(err, clierr.ErrDefinition))
+	assert.Equal(t, cli.Name.Value, "")
+}
+
+func TestParse_SubcommandDoubleDash(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	os.Args = []string{"app", "run", "--", "--weird"}
+
+	cli := struct {
+		Clifford `name:"app"`
+
+		Run struct {
+			Subcommand
+			File struct {
+				Value string
+				Required
+			}
+			Verbose struct {
+				Value    bool
+				Clifford `long:"weird"`
+			}
+		}
+	}{}
+
+	err := Parse(&cli)
+	assert.Nil(t, err)
+	assert.True(t, bool(cli.Run.Subcommand))
+	assert.Equal(t, cli.Run.File.Value, "--weird")
+	assert.False(t, cli.Run.Verbose.Value)
+}
+
+func TestParse_RootDoubleDash(t *testing.T) {
+	type cli struct {
+		Clifford `name:"app"`
+
+		Verbose bool `long:"verbose"`
+		Files   []string
+	}
+
+	// Flags before "--" are parsed; everything after it is positional
+	c := cli{}
+	assert.Nil(t, ParseArgs(&c, []string{"--verbose", "--", "--weird", "-v"}))
+	assert.True(t, c.Verbose)
+	assert.Equal(t, strings.Join(c.Files, ","), "--weird,-v")
+
+	c = cli{}
+	assert.Nil(t, ParseArgs(&c, []string{"a", "--", "--verbose"}))
+	assert.False(t, c.Verbose)
+	assert.Equal(t, strings.Join(c.Files, ","), "a,--verbose")
+}
+
+func TestParse_BoolFlagsAroundValueFlag(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	type cli struct {
+		Clifford `name:"app"`
+
+		Verbose struct {
+			Value    bool
+			Clifford `short:"v"`
+		}
+		Port struct {
+			Value    int
+			Clifford `long:"port"`
+		}
+		Quiet struct {
+			Value    bool
+			Clifford `short:"q"`
+		}
+	}
+
+	for _, args := range [][]string{
+		{"-v", "--port", "80", "-q"},
+		{"--port", "80", "-v", "-q"},
+		{"-q", "-v", "--port", "80"},
+	} {
+		os.Args = append([]string{"app"}, args...)
+		c := cli{}
+		err := Parse(&c)
+		assert.Nil(t, err)
+		assert.True(t, c.Verbose.Value)
+		assert.Equal(t, c.Port.Value, 80)
+		assert.True(t, c.Quiet.Value)
+	}
+}
+
+func TestParse_RequiredIf(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	type cli struct {
+		Clifford `name:"app"`
+
+		Save struct {
+			Value    bool
+			Clifford `long:"save"`
+		}
+		OutputFile struct {
+			Value    string
+			Clifford `long:"output-file" required_if:"save"`
+		}
+	}
+
+	// Without --save the output file is optional
+	os.Args = []string{"app"}
+	c := cli{}
+	assert.Nil(t, Parse(&c))
+
+	// With --save and an output file the condition is satisfied
+	os.Args = []string{"app", "--save", "--output-file", "out.txt"}
+	c = cli{}
+	assert.Nil(t, Parse(&c))
+	assert.Equal(t, c.OutputFile.Value, "out.txt")
+
+	// With --save alone the output file is missing
+	os.Args = []string{"app", "--save"}
+	c = cli{}
+	err := Parse(&c)
+	assert.NotNil(t, err)
+	var me clierr.MissingArgError
+	assert.True(t, stderrs.As(err, &me))
+	assert.Equal(t, me.Field, "OutputFile")
+	assert.Equal(t, me.Condition, "--save")
+	assert.True(t, strings.Contains(err.Error(), "required when --save is given"))
+}
+
+func TestParse_MultiWordValues(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	// The shell delivers a quoted value as a single token
+	os.Args = []string{"cmd", "--name", "John Doe", "a file.txt", "-t", "  padded  "}
+
+	cli := struct {
+		Clifford `name:"mytool"`
+
+		Name struct {
+			Value    string
+			Clifford `long:"name"`
+		}
+		Title struct {
+			Value    string
+			Clifford `short:"t"`
+		}
+		File struct {
+			Value string
+			Required
+		}
+	}{}
+
+	err := Parse(&cli)
+	assert.Nil(t, err)
+	assert.Equal(t, cli.Name.Value, "John Doe")
+	assert.Equal(t, cli.File.Value, "a file.txt")
+	assert.Equal(t, cli.Title.Value, "  padded  ")
+}
+
+func TestParse_NegativeNumberPositional(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	type cli struct {
+		Clifford `name:"app"`
+
+		Number struct {
+			Value float64
+		}
+		Offset struct {
+			Value    int
+			Clifford `long:"offset"`
+		}
+		One struct {
+			Value    bool
+			Clifford `short:"1"`
+		}
+	}
+
+	os.Args = []string{"app", "-5"}
+	c := cli{}
+	assert.Nil(t, Parse(&c))
+	assert.Equal(t, c.Number.Value, -5.0)
+
+	os.Args = []string{"app", "--offset", "-3", "-2.5"}
+	c = cli{}
+	assert.Nil(t, Parse(&c))
+	assert.Equal(t, c.Offset.Value, -3)
+	assert.Equal(t, c.Number.Value, -2.5)
+
+	// A declared flag with a numeric name is still a flag
+	os.Args = []string{"app", "-1"}
+	c = cli{}
+	assert.Nil(t, Parse(&c))
+	assert.True(t, c.One.Value)
+	assert.Equal(t, c.Number.Value, 0.0)
+}
+
+func TestParse_ExplicitPositions(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	os.Args = []string{"cmd", "src.txt", "dst.txt", "extra"}
+
+	cli := struct {
+		Clifford `name:"cp"`
+
+		Dest struct {
+			Value    string
+			Clifford `pos:"2"`
+		}
+		Rest struct {
+			Value string
+		}
+		Source struct {
+			Value    string
+			Clifford `pos:"1"`
+		}
+	}{}
+
+	err := Parse(&cli)
+	assert.Nil(t, err)
+	assert.Equal(t, cli.Source.Value, "src.txt")
+	assert.Equal(t, cli.Dest.Value, "dst.txt")
+	assert.Equal(t, cli.Rest.Value, "extra")
+}
+
+func TestParse_DuplicatePositions(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	os.Args = []string{"cmd", "a", "b"}
+
+	cli := struct {
+		Clifford `name:"cmd"`
+
+		First struct {
+			Value    string
+			Clifford `pos:"1"`
+		}
+		Second struct {
+			Value    string
+			Clifford `pos:"1"`
+		}
+	}{}
+
+	err := Parse(&cli)
+	var de clierr.DefinitionError
+	assert.True(t, stderrs.As(err, &de))
+	assert.True(t, strings.Contains(err.Error(), "duplicate position 1"))
+}
+
+func TestParse_MapValues(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	os.Args = []string{"cmd",
+		"--header", "Authorization=Bearer xyz=abc",
+		"-H", "Accept=text/plain",
+		"--meta", "X-Trace: a:b",
+		"--limit", "cpu=2",
+	}
+
+	cli := struct {
+		Clifford `name:"cmd"`
+
+		Header struct {
+			Value    map[string]string
+			Clifford `short:"H" long:"header"`
+		}
+		Meta struct {
+			Value    map[string]string
+			Clifford `long:"meta" kv_separator:":"`
+		}
+		Limit struct {
+			Value    map[string]int
+			Clifford `long:"limit"`
+		}
+	}{}
+
+	err := Parse(&cli)
+	assert.Nil(t, err)
+	// Only the first separator splits the entry
+	assert.Equal(t, cli.Header.Value["Authorization"], "Bearer xyz=abc")
+	assert.Equal(t, cli.Header.Value["Accept"], "text/plain")
+	assert.Equal(t, cli.Meta.Value["X-Trace"], "a:b")
+	assert.Equal(t, cli.Limit.Value["cpu"], 2)
+}
+
+func TestParse_MapEntryWithoutSeparator(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	os.Args = []string{"cmd", "--header", "novalue"}
+
+	cli := struct {
+		Clifford `name:"cmd"`
+
+		Header struct {
+			Value    map[string]string
+			Clifford `long:"header"`
+		}
+	}{}
+
+	err := Parse(&cli)
+	var pe clierr.ParseError
+	assert.True(t, stderrs.As(err, &pe))
+	assert.True(t, strings.Contains(err.Error(), "expected key=value"))
+}
+
+func TestParse_InlineMapFlag(t *testing.T) {
+	type cli struct {
+		Clifford `name:"deploy"`
+
+		Labels map[string]string `long:"label" sep:","`
+	}
+
+	c := cli{}
+	assert.Nil(t, ParseArgs(&c, []string{"--label", "env=prod", "--label", "team=core,tier=web"}))
+	assert.Equal(t, len(c.Labels), 3)
+	assert.Equal(t, c.Labels["env"], "prod")
+	assert.Equal(t, c.Labels["tier"], "web")
+
+	// The map is only allocated once a label is given
+	c = cli{}
+	assert.Nil(t, ParseArgs(&c, nil))
+	assert.True(t, c.Labels == nil)
+
+	var pe clierr.ParseError
+	assert.True(t, stderrs.As(ParseArgs(&cli{}, []string{"--label", "env"}), &pe))
+}
+
+func TestParse_DigitShortFlag(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	os.Args = []string{"head", "notes.txt", "-3", "-5"}
+
+	cli := struct {
+		Clifford `name:"head"`
+
+		Five struct {
+			Value    bool
+			Clifford `short:"5" desc:"Show five lines"`
+		}
+		File struct {
+			Value string
+		}
+		Offset struct {
+			Value int
+		}
+	}{}
+
+	err := Parse(&cli)
+	assert.Nil(t, err)
+	// The declared -5 wins over the negative number heuristic, while the
+	// undeclared -3 is still a positional value
+	assert.True(t, cli.Five.Value)
+	assert.Equal(t, cli.File.Value, "notes.txt")
+	assert.Equal(t, cli.Offset.Value, -3)
+}
+
+func TestParse_MapPairsInOneValue(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	os.Args = []string{"cmd", "--labels", "a=1,b=2", "--labels", "c=3"}
+
+	cli := struct {
+		Clifford `name:"cmd"`
+
+		Labels struct {
+			Value    map[string]int
+			Clifford `long:"labels" separator:","`
+		}
+	}{}
+
+	err := Parse(&cli)
+	assert.Nil(t, err)
+	assert.Equal(t, len(cli.Labels.Value), 3)
+	assert.Equal(t, cli.Labels.Value["a"], 1)
+	assert.Equal(t, cli.Labels.Value["b"], 2)
+	assert.Equal(t, cli.Labels.Value["c"], 3)
+}
+
+func TestParse_GlobalFlagBeforeSubcommand(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	type cli struct {
+		Clifford `name:"app"`
+
+		Dir struct {
+			Value    string
+			Clifford `short:"C"`
+		}
+		Verbose bool `short:"v"`
+
+		Serve struct {
+			Subcommand
+			Port struct {
+				Value    int
+				Clifford `long:"port"`
+			}
+		}
+	}
+
+	os.Args = []string{"app", "-C", "dir", "serve", "--port", "80"}
+	c := cli{}
+	err := Parse(&c)
+	assert.Nil(t, err)
+	assert.Equal(t, c.Dir.Value, "dir")
+	assert.True(t, bool(c.Serve.Subcommand))
+	assert.Equal(t, c.Serve.Port.Value, 80)
+
+	// A global boolean flag must not swallow the subcommand name
+	os.Args = []string{"app", "-v", "serve", "--port", "80"}
+	c = cli{}
+	err = Parse(&c)
+	assert.Nil(t, err)
+	assert.True(t, c.Verbose)
+	assert.True(t, bool(c.Serve.Subcommand))
+	assert.Equal(t, c.Serve.Port.Value, 80)
+
+	// A value that names a subcommand is taken when the subcommand follows
+	os.Args = []string{"app", "-C", "serve", "serve", "--port", "80"}
+	c = cli{}
+	err = Parse(&c)
+	assert.Nil(t, err)
+	assert.Equal(t, c.Dir.Value, "serve")
+	assert.True(t, bool(c.Serve.Subcommand))
+	assert.Equal(t, c.Serve.Port.Value, 80)
+}
+
+func TestParse_PairedBoolFlag(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	type cli struct {
+		Clifford `name:"app"`
+
+		Color struct {
+			Value    bool `default:"true"`
+			Clifford `long:"color" pair:"enable,disable"`
+		}
+		Cache struct {
+			Value    bool
+			Clifford `pair:"with,without"`
+		}
+	}
+
+	cases := []struct {
+		args         []string
+		color, cache bool
+	}{
+		{[]string{}, true, false},
+		{[]string{"--disable-color", "--with-cache"}, false, true},
+		{[]string{"--enable-color", "--without-cache"}, true, false},
+		{[]string{"--enable-color", "--disable-color"}, false, false},
+		{[]string{"--disable-color", "--enable-color"}, true, false},
+	}
+	for _, tc := range cases {
+		os.Args = append([]string{"app"}, tc.args...)
+		c := cli{}
+		err := Parse(&c)
+		assert.Nil(t, err)
+		assert.Equal(t, c.Color.Value, tc.color)
+		assert.Equal(t, c.Cache.Value, tc.cache)
+	}
+}
+
+func TestParse_InlineFlagValues(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	os.Args = []string{"cmd", "--port=8080", "--name=Some Value With Spaces", "--tag=", "-l=debug", "--verbose=false", "file.txt"}
+
+	cli := struct {
+		Clifford `name:"cmd"`
+
+		Port struct {
+			Value    int
+			Clifford `long:"port"`
+		}
+		Name struct {
+			Value    string
+			Clifford `long:"name"`
+		}
+		Tag struct {
+			Value    string `default:"latest"`
+			Clifford `long:"tag"`
+		}
+		Level struct {
+			Value    string
+			Clifford `short:"l"`
+		}
+		Verbose struct {
+			Value    bool `default:"true"`
+			Clifford `long:"verbose"`
+		}
+		File struct {
+			Value string
+		}
+	}{}
+
+	err := Parse(&cli)
+	assert.Nil(t, err)
+	assert.Equal(t, cli.Port.Value, 8080)
+	assert.Equal(t, cli.Name.Value, "Some Value With Spaces")
+	// An empty inline value is kept rather than dropped in favour of the default
+	assert.Equal(t, cli.Tag.Value, "")
+	assert.Equal(t, cli.Level.Value, "debug")
+	assert.False(t, cli.Verbose.Value)
+	assert.Equal(t, cli.File.Value, "file.txt")
+}
+
+func TestParse_NegativeFlagValues(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	os.Args = []string{"mytool", "--offset", "-5", "--temp", "-3.14", "-v"}
+
+	cli := struct {
+		Clifford `name:"mytool"`
+
+		Offset struct {
+			Value    int
+			Clifford `long:"offset"`
+		}
+		Temp struct {
+			Value    float64
+			Clifford `long:"temp"`
+		}
+		Verbose struct {
+			Value    bool
+			Clifford `short:"v"`
+		}
+	}{}
+
+	err := Parse(&cli)
+	assert.Nil(t, err)
+	assert.Equal(t, cli.Offset.Value, -5)
+	assert.Equal(t, cli.Temp.Value, -3.14)
+	// A flag-like token after a value is still a flag
+	assert.True(t, cli.Verbose.Value)
+}
+
+func TestParse_NegativeDurationsAndSizes(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	os.Args = []string{"mytool", "--offset", "-5m", "--delta", "-10MB", "--window", "-1h30m"}
+
+	cli := struct {
+		Clifford `name:"mytool"`
+
+		Offset struct {
+			Value    time.Duration
+			Clifford `long:"offset"`
+		}
+		Delta struct {
+			Value    string
+			Clifford `long:"delta"`
+		}
+		Window time.Duration `long:"window"`
+	}{}
+
+	err := Parse(&cli)
+	assert.Nil(t, err)
+	assert.Equal(t, cli.Offset.Value, -5*time.Minute)
+	assert.Equal(t, cli.Delta.Value, "-10MB")
+	assert.Equal(t, cli.Window, -90*time.Minute)
+}
+
+func TestParse_RepeatableFlags(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	type cli struct {
+		Clifford `name:"app"`
+
+		Tags struct {
+			Value    []string `default:"latest"`
+			Clifford `short:"t" long:"tag"`
+		}
+		Ports []int `long:"port"`
+	}
+
+	os.Args = []string{"app", "--tag", "a", "--port", "80", "--tag", "b", "--port", "443", "--tag", "c"}
+	c := cli{}
+	err := Parse(&c)
+	assert.Nil(t, err)
+	assert.Equal(t, len(c.Tags.Value), 3)
+	assert.Equal(t, c.Tags.Value[0], "a")
+	assert.Equal(t, c.Tags.Value[1], "b")
+	assert.Equal(t, c.Tags.Value[2], "c")
+	assert.Equal(t, len(c.Ports), 2)
+	assert.Equal(t, c.Ports[0], 80)
+	assert.Equal(t, c.Ports[1], 443)
+
+	// The default only applies when the flag is never given
+	os.Args = []string{"app"}
+	c = cli{}
+	err = Parse(&c)
+	assert.Nil(t, err)
+	assert.Equal(t, len(c.Tags.Value), 1)
+	assert.Equal(t, c.Tags.Value[0], "latest")
+	assert.Equal(t, len(c.Ports), 0)
+}
+
+func TestParse_SliceSeparator(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	os.Args = []string{"app", "--tag", "a,b", "--tag", "c"}
+
+	cli := struct {
+		Clifford `name:"app"`
+
+		Tags []string `long:"tag" separator:","`
+	}{}
+
+	err := Parse(&cli)
+	assert.Nil(t, err)
+	assert.Equal(t, strings.Join(cli.Tags, " "), "a b c")
+}
+
+func TestParse_FlagMissingValue(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	type cli struct {
+		Clifford `name:"app"`
+
+		Port struct {
+			Value    int `default:"8080"`
+			Clifford `short:"p" long:"port"`
+		}
+		Verbose struct {
+			Value    bool
+			Clifford `short:"v" long:"verbose"`
+		}
+	}
+
+	os.Args = []string{"app", "--port"}
+	err := Parse(&cli{})
+	assert.NotNil(t, err)
+	var pe clierr.ParseError
+	assert.True(t, stderrs.As(err, &pe))
+	assert.Equal(t, err.Error(), "flag --port requires a value")
+
+	// A flag directly following is not a value either
+	os.Args = []string{"app", "-p", "--verbose"}
+	err = Parse(&cli{})
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "flag -p requires a value")
+
+	// Boolean flags need no value
+	os.Args = []string{"app", "--port", "80", "--verbose"}
+	c := cli{}
+	err = Parse(&c)
+	assert.Nil(t, err)
+	assert.Equal(t, c.Port.Value, 80)
+	assert.True(t, c.Verbose.Value)
+}
+
+func TestParse_NumericSlices(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	type cli struct {
+		Clifford `name:"app"`
+
+		IDs struct {
+			Value    []int
+			Clifford `long:"id" sep:","`
+		}
+		Weights []float64 `long:"weight"`
+	}
+
+	os.Args = []string{"app", "--id", "1", "--id", "2,3", "--weight", "0.5", "--weight", "1.25"}
+	c := cli{}
+	err := Parse(&c)
+	assert.Nil(t, err)
+	assert.Equal(t, len(c.IDs.Value), 3)
+	assert.Equal(t, c.IDs.Value[0], 1)
+	assert.Equal(t, c.IDs.Value[1], 2)
+	assert.Equal(t, c.IDs.Value[2], 3)
+	assert.Equal(t, len(c.Weights), 2)
+	assert.Equal(t, c.Weights[1], 1.25)
+
+	// A malformed element is reported, naming the offending token
+	os.Args = []string{"app", "--id", "1,x,3"}
+	err = Parse(&cli{})
+	assert.NotNil(t, err)
+	var ie clierr.InvalidValueError
+	assert.True(t, stderrs.As(err, &ie))
+	assert.Equal(t, ie.Field, "IDs")
+	assert.Equal(t, ie.Value, "x")
+	assert.True(t, stderrs.Is(err, clierr.ErrInvalidValue))
+
+	os.Args = []string{"app", "--weight", "heavy"}
+	err = Parse(&cli{})
+	assert.True(t, stderrs.As(err, &ie))
+	assert.Equal(t, ie.Field, "Weights")
+}
+
+func TestParse_WideIntegerKinds(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	type cli struct {
+		Clifford `name:"app"`
+
+		Offset struct {
+			Value    int64
+			Clifford `long:"offset"`
+		}
+		Size struct {
+			Value    uint64
+			Clifford `long:"size"`
+		}
+		Workers uint     `long:"workers"`
+		IDs     []uint64 `long:"id"`
+	}
+
+	os.Args = []string{"app", "--offset", "-9000000000", "--size", "18446744073709551615", "--workers", "4", "--id", "7"}
+	c := cli{}
+	err := Parse(&c)
+	assert.Nil(t, err)
+	assert.Equal(t, c.Offset.Value, int64(-9000000000))
+	assert.Equal(t, c.Size.Value, uint64(18446744073709551615))
+	assert.Equal(t, c.Workers, uint(4))
+	assert.Equal(t, c.IDs[0], uint64(7))
+
+	var ie clierr.InvalidValueError
+	for _, args := range [][]string{
+		{"app", "--size", "-1"},
+		{"app", "--size", "18446744073709551616"},
+		{"app", "--offset", "abc"},
+		{"app", "--workers", "four"},
+	} {
+		os.Args = args
+		err = Parse(&cli{})
+		assert.True(t, stderrs.As(err, &ie))
+		assert.Equal(t, ie.Value, args[2])
+	}
+}
+
+func TestParse_FloatKinds(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	type cli struct {
+		Clifford `name:"app"`
+
+		Ratio struct {
+			Value    float32
+			Clifford `long:"ratio"`
+		}
+		Scale float64 `long:"scale"`
+	}
+
+	os.Args = []string{"app", "--ratio", "0.25", "--scale", "1.5"}
+	c := cli{}
+	err := Parse(&c)
+	assert.Nil(t, err)
+	assert.Equal(t, c.Ratio.Value, float32(0.25))
+	assert.Equal(t, c.Scale, 1.5)
+
+	var ie clierr.InvalidValueError
+	os.Args = []string{"app", "--scale", "big"}
+	err = Parse(&cli{})
+	assert.True(t, stderrs.As(err, &ie))
+	assert.Equal(t, ie.Field, "Scale")
+	assert.Equal(t, ie.Value, "big")
+
+	os.Args = []string{"app", "--ratio", "1e39"}
+	err = Parse(&cli{})
+	assert.True(t, stderrs.As(err, &ie))
+	assert.Equal(t, ie.Field, "Ratio")
+}
+
+// rgb is a custom value type that parses itself from "r,g,b".
+type rgb struct{ R, G, B uint8 }
+
+func (c *rgb) UnmarshalText(text []byte) error {
+	_, err := fmt.Sscanf(string(text), "%d,%d,%d", &c.R, &c.G, &c.B)
+	return err
+}
+
+func TestParse_TextUnmarshaler(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	type cli struct {
+		Clifford `name:"app"`
+
+		Color struct {
+			Value    rgb
+			Clifford `long:"color"`
+		}
+		Bind  net.IP    `long:"bind"`
+		Since time.Time `long:"since"`
+	}
+
+	os.Args = []string{"app", "--color", "255,128,0", "--bind", "10.0.0.1", "--since", "2024-01-02T15:04:05Z"}
+	c := cli{}
+	err := Parse(&c)
+	assert.Nil(t, err)
+	assert.Equal(t, c.Color.Value, rgb{255, 128, 0})
+	assert.Equal(t, c.Bind.String(), "10.0.0.1")
+	assert.Equal(t, c.Since.Year(), 2024)
+
+	os.Args = []string{"app", "--bind", "not-an-ip"}
+	err = Parse(&cli{})
+	var ie clierr.InvalidValueError
+	assert.True(t, stderrs.As(err, &ie))
+	assert.Equal(t, ie.Field, "Bind")
+	assert.Equal(t, ie.Value, "not-an-ip")
+}
+
+func TestParse_EnvFallback(t *testing.T) {
+	type cli struct {
+		Clifford `name:"app"`
+
+		Port struct {
+			Value    int `default:"8080"`
+			Clifford `long:"port" env:"APP_PORT"`
+		}
+	}
+
+	// The environment takes over from the default
+	t.Setenv("APP_PORT", "9090")
+	c := cli{}
+	assert.Nil(t, ParseArgs(&c, nil))
+	assert.Equal(t, c.Port.Value, 9090)
+
+	// The command line wins over the environment
+	c = cli{}
+	assert.Nil(t, ParseArgs(&c, []string{"--port", "7070"}))
+	assert.Equal(t, c.Port.Value, 7070)
+
+	// An empty variable counts as unset rather than as an empty value
+	t.Setenv("APP_PORT", "")
+	c = cli{}
+	assert.Nil(t, ParseArgs(&c, nil))
+	assert.Equal(t, c.Port.Value, 8080)
+}
+
+func TestParse_EnvBool(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	os.Args = []string{"app"}
+
+	type cli struct {
+		Clifford `name:"app"`
+
+		Verbose struct {
+			Value    bool `default:"true"`
+			Clifford `long:"verbose" env:"APP_VERBOSE"`
+		}
+	}
+
+	for _, tc := range []struct {
+		env  string
+		want bool
+	}{
+		{"0", false},
+		{"1", true},
+		{"true", true},
+		{"false", false},
+		{"", false},
+	} {
+		t.Setenv("APP_VERBOSE", tc.env)
+		c := cli{}
+		assert.Nil(t, Parse(&c))
+		assert.Equal(t, c.Verbose.Value, tc.want)
+	}
+
+	// Unset falls through to the default
+	os.Unsetenv("APP_VERBOSE")
+	c := cli{}
+	assert.Nil(t, Parse(&c))
+	assert.True(t, c.Verbose.Value)
+
+	// The command line still wins
+	t.Setenv("APP_VERBOSE", "0")
+	os.Args = []string{"app", "--verbose"}
+	c = cli{}
+	assert.Nil(t, Parse(&c))
+	assert.True(t, c.Verbose.Value)
+}
+
+// listValue is a flag.Value that accumulates every value it is given.
+type listValue []string
+
+func (l *listValue) String() string     { return strings.Join(*l, ",") }
+func (l *listValue) Set(v string) error { *l = append(*l, v); return nil }
+
+// levelValue is a flag.Value that accepts a fixed set of levels.
+type levelValue struct{ name string }
+
+func (l *levelValue) String() string { return l.name }
+func (l *levelValue) Set(v string) error {
+	if v != "debug" && v != "info" {
+		return fmt.Errorf("unknown level %q", v)
+	}
+	l.name = v
+	return nil
+}
+
+func TestParse_FlagValue(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	type cli struct {
+		Clifford `name:"app"`
+
+		Include struct {
+			Value    listValue
+			Clifford `short:"I" long:"include"`
+		}
+		Level levelValue `long:"level"`
+	}
+
+	os.Args = []string{"app", "--include", "a", "--level", "debug", "--include", "b"}
+	c := cli{}
+	err := Parse(&c)
+	assert.Nil(t, err)
+	assert.Equal(t, c.Include.Value.String(), "a,b")
+	assert.Equal(t, c.Level.name, "debug")
+
+	os.Args = []string{"app", "--level", "loud"}
+	err = Parse(&cli{})
+	var ie clierr.InvalidValueError
+	assert.True(t, stderrs.As(err, &ie))
+	assert.Equal(t, ie.Field, "Level")
+	assert.Equal(t, ie.Value, "loud")
+}
+
+func TestParse_InvalidScalarValues(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	type cli struct {
+		Clifford `name:"app"`
+
+		Port struct {
+			Value    int `default:"8080"`
+			Clifford `long:"port"`
+		}
+		Debug struct {
+			Value    bool
+			Clifford `long:"debug"`
+		}
+		Timeout time.Duration `long:"timeout"`
+	}
+
+	for _, tc := range []struct {
+		args        []string
+		field, kind string
+	}{
+		{[]string{"app", "--port", "notanumber"}, "Port", "int"},
+		{[]string{"app", "--debug=maybe"}, "Debug", "bool"},
+		{[]string{"app", "--timeout", "soon"}, "Timeout", "time.Duration"},
+	} {
+		os.Args = tc.args
+		c := cli{}
+		err := Parse(&c)
+		var ie clierr.InvalidValueError
+		assert.True(t, stderrs.As(err, &ie))
+		assert.Equal(t, ie.Field, tc.field)
+		assert.Equal(t, ie.Kind, tc.kind)
+	}
+
+	os.Args = []string{"app", "--port", "notanumber"}
+	err := Parse(&cli{})
+	assert.Equal(t, err.Error(), `invalid value for Port: "notanumber" is not a valid int`)
+}
+
+func TestParse_UnknownFlag(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	type cli struct {
+		Clifford `name:"app"`
+
+		Port struct {
+			Value    int `default:"8080"`
+			Clifford `short:"p" long:"port"`
+		}
+	}
+
+	os.Args = []string{"app", "--prot", "9090"}
+	err := Parse(&cli{})
+	var ue clierr.UnknownFlagError
+	assert.True(t, stderrs.As(err, &ue))
+	assert.Equal(t, ue.Name, "--prot")
+	assert.Equal(t, ue.Suggestion, "--port")
+	assert.Equal(t, err.Error(), `unknown flag: --prot (did you mean "--port"?)`)
+	assert.True(t, stderrs.Is(err, clierr.ErrUnknownFlag))
+
+	// The first unknown flag on the command line is reported
+	os.Args = []string{"app", "--zzz", "-p", "1", "--prot=2"}
+	err = Parse(&cli{})
+	assert.True(t, stderrs.As(err, &ue))
+	assert.Equal(t, ue.Name, "--zzz")
+
+	// Tools that forward extra flags can opt out
+	type lenient struct {
+		Clifford `name:"app" allow_unknown:"true"`
+
+		Port struct {
+			Value    int
+			Clifford `long:"port"`
+		}
+	}
+	os.Args = []string{"app", "--extra", "--port", "80"}
+	c := lenient{}
+	assert.Nil(t, Parse(&c))
+	assert.Equal(t, c.Port.Value, 80)
+}
+
+func TestParseArgs_ExplicitSlice(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	os.Args = []string{"app", "--name", "from-os-args"}
+
+	cli := struct {
+		Clifford `name:"app"`
+
+		Name struct {
+			Value    string
+			Clifford `long:"name"`
+		}
+		Serve struct {
+			Subcommand
+			Port int `long:"port"`
+		}
+	}{}
+
+	err := ParseArgs(&cli, []string{"--name", "explicit", "serve", "--port", "80"})
+	assert.Nil(t, err)
+	assert.Equal(t, cli.Name.Value, "explicit")
+	assert.True(t, bool(cli.Serve.Subcommand))
+	assert.Equal(t, cli.Serve.Port, 80)
+	assert.Equal(t, os.Args[2], "from-os-args")
+}
+
+func TestParse_RestPositional(t *testing.T) {
+	type cli struct {
+		Clifford `name:"app"`
+
+		Exec struct {
+			Subcommand
+			Dir     string `short:"C"`
+			Command struct {
+				Value []string `rest_positional:"true"`
+			}
+		}
+	}
+
+	c := cli{}
+	err := ParseArgs(&c, []string{"exec", "ls", "-la", "/tmp"})
+	assert.Nil(t, err)
+	assert.Equal(t, strings.Join(c.Exec.Command.Value, " "), "ls -la /tmp")
+	assert.Equal(t, c.Exec.Dir, "")
+
+	// Flags before the first captured token still belong to the command
+	c = cli{}
+	err = ParseArgs(&c, []string{"exec", "-C", "/tmp", "grep", "-v", "--", "x"})
+	assert.Nil(t, err)
+	assert.Equal(t, c.Exec.Dir, "/tmp")
+	assert.Equal(t, strings.Join(c.Exec.Command.Value, " "), "grep -v -- x")
+
+	type invalid struct {
+		Clifford `name:"app"`
+
+		Command string `rest_positional:"true"`
+	}
+	err = ParseArgs(&invalid{}, []string{"ls"})
+	var de clierr.DefinitionError
+	assert.True(t, stderrs.As(err, &de))
+}
+
+func TestParse_Choices(t *testing.T) {
+	type cli struct {
+		Clifford `name:"app"`
+
+		Level struct {
+			Value    string `default:"info"`
+			Clifford `long:"level" choices:"debug,info,warn,error"`
+		}
+		Format string   `long:"format" choices:"json,text" choices_ci:"true"`
+		Tags   []string `long:"tag" choices:"a,b"`
+	}
+
+	c := cli{}
+	assert.Nil(t, ParseArgs(&c, []string{"--level", "warn", "--format", "JSON", "--tag", "a", "--tag", "b"}))
+	assert.Equal(t, c.Level.Value, "warn")
+	assert.Equal(t, c.Format, "json")
+	assert.Equal(t, len(c.Tags), 2)
+
+	// Defaults are used as is
+	c = cli{}
+	assert.Nil(t, ParseArgs(&c, nil))
+	assert.Equal(t, c.Level.Value, "info")
+
+	// Matching is case-sensitive unless choices_ci is set
+	err := ParseArgs(&cli{}, []string{"--level", "WARN"})
+	var ce clierr.InvalidChoiceError
+	assert.True(t, stderrs.As(err, &ce))
+	assert.Equal(t, ce.Field, "Level")
+	assert.Equal(t, err.Error(), `invalid value for Level: "WARN" (choices: debug, info, warn, error)`)
+	assert.True(t, stderrs.Is(err, clierr.ErrInvalidChoice))
+
+	err = ParseArgs(&cli{}, []string{"--tag", "a", "--tag", "c"})
+	assert.True(t, stderrs.As(err, &ce))
+	assert.Equal(t, ce.Value, "c")
+}
+
+func TestParse_NumericRange(t *testing.T) {
+	type cli struct {
+		Clifford `name:"app"`
+
+		Port struct {
+			Value    int `default:"8080"`
+			Clifford `long:"port" min:"1" max:"65535"`
+		}
+		Ratio   float64 `long:"ratio" min:"0" max:"1"`
+		Weights []uint  `long:"weight" max:"10"`
+	}
+
+	c := cli{}
+	assert.Nil(t, ParseArgs(&c, []string{"--port", "65535", "--ratio", "0.5", "--weight", "3", "--weight", "10"}))
+	assert.Equal(t, c.Port.Value, 65535)
+
+	var re clierr.RangeError
+	err := ParseArgs(&cli{}, []string{"--port", "0"})
+	assert.True(t, stderrs.As(err, &re))
+	assert.Equal(t, re.Bound, "min")
+	assert.Equal(t, err.Error(), "invalid value for Port: 0 is below the minimum of 1")
+	assert.True(t, stderrs.Is(err, clierr.ErrOutOfRange))
+
+	err = ParseArgs(&cli{}, []string{"--port", "70000"})
+	assert.True(t, stderrs.As(err, &re))
+	assert.Equal(t, err.Error(), "invalid value for Port: 70000 is above the maximum of 65535")
+
+	err = ParseArgs(&cli{}, []string{"--ratio", "1.5"})
+	assert.True(t, stderrs.As(err, &re))
+	assert.Equal(t, re.Field, "Ratio")
+
+	err = ParseArgs(&cli{}, []string{"--weight", "3", "--weight", "11"})
+	assert.True(t, stderrs.As(err, &re))
+	assert.Equal(t, re.Value, "11")
+
+	// A bound that does not parse is a definition error
+	type invalid struct {
+		Clifford `name:"app"`
+
+		Port int `long:"port" min:"one"`
+	}
+	var de clierr.DefinitionError
+	assert.True(t, stderrs.As(ParseArgs(&invalid{}, []string{"--port", "1"}), &de))
+	assert.True(t, stderrs.As(Validate(&invalid{}), &de))
+}
+
+func TestParse_Pattern(t *testing.T) {
+	type cli struct {
+		Clifford `name:"app"`
+
+		Name struct {
+			Value    string
+			Clifford `long:"name" pattern:"^[a-z][a-z0-9-]*$"`
+		}
+		Version string   `long:"version" pattern:"^\\d+\\.\\d+\\.\\d+$"`
+		Hosts   []string `long:"host" pattern:"^[a-z.]+$"`
+	}
+
+	c := cli{}
+	assert.Nil(t, ParseArgs(&c, []string{"--name", "web-1", "--version", "1.2.3", "--host", "a.example"}))
+	assert.Equal(t, c.Name.Value, "web-1")
+
+	var pe clierr.PatternError
+	err := ParseArgs(&cli{}, []string{"--name", "Web"})
+	assert.True(t, stderrs.As(err, &pe))
+	assert.Equal(t, err.Error(), `invalid value for Name: "Web" does not match ^[a-z][a-z0-9-]*$`)
+	assert.True(t, stderrs.Is(err, clierr.ErrPatternMismatch))
+
+	err = ParseArgs(&cli{}, []string{"--version", "1.2"})
+	assert.True(t, stderrs.As(err, &pe))
+	assert.Equal(t, pe.Field, "Version")
+
+	err = ParseArgs(&cli{}, []string{"--host", "ok", "--host", "NOT_OK"})
+	assert.True(t, stderrs.As(err, &pe))
+	assert.Equal(t, pe.Value, "NOT_OK")
+
+	// A pattern that does not compile is a definition error, not a user error
+	type invalid struct {
+		Clifford `name:"app"`
+
+		Name string `long:"name" pattern:"[a-z"`
+	}
+	var de clierr.DefinitionError
+	assert.True(t, stderrs.As(ParseArgs(&invalid{}, []string{"--name", "x"}), &de))
+	assert.False(t, stderrs.As(ParseArgs(&invalid{}, []string{"--name", "x"}), &pe))
+	assert.True(t, stderrs.As(Validate(&invalid{}), &de))
+}
+
+type (
+	logLevel string
+	portNum  int
+	ratio    float64
+	percent  uint8
+)
+
+func TestParse_NamedScalarTypes(t *testing.T) {
+	type cli struct {
+		Clifford `name:"app"`
+
+		Level struct {
+			Value    logLevel `default:"info"`
+			Clifford `long:"level" choices:"debug,info" choices_ci:"true"`
+		}
+		Port   portNum    `long:"port" min:"1"`
+		Ratio  ratio      `long:"ratio" max:"1"`
+		Levels []logLevel `long:"also" choices:"debug,info"`
+		Ports  []portNum  `long:"ports" sep:","`
+		Share  percent    `long:"share" max:"100"`
+	}
+
+	c := cli{}
+	err := ParseArgs(&c, []string{"--level", "DEBUG", "--port", "8080", "--ratio", "0.25", "--also", "info", "--ports", "1,2", "--share", "40"})
+	assert.Nil(t, err)
+	assert.Equal(t, c.Share, percent(40))
+	assert.Equal(t, c.Level.Value, logLevel("debug"))
+	assert.Equal(t, c.Port, portNum(8080))
+	assert.Equal(t, c.Ratio, ratio(0.25))
+	assert.Equal(t, len(c.Levels), 1)
+	assert.Equal(t, c.Levels[0], logLevel("info"))
+	assert.Equal(t, len(c.Ports), 2)
+	assert.Equal(t, c.Ports[1], portNum(2))
+
+	c = cli{}
+	assert.Nil(t, ParseArgs(&c, nil))
+	assert.Equal(t, c.Level.Value, logLevel("info"))
+
+	var ce clierr.InvalidChoiceError
+	assert.True(t, stderrs.As(ParseArgs(&cli{}, []string{"--also", "trace"}), &ce))
+	var re clierr.RangeError
+	assert.True(t, stderrs.As(ParseArgs(&cli{}, []string{"--port", "0"}), &re))
+
+	var ie clierr.InvalidValueError
+	err = ParseArgs(&cli{}, []string{"--port", "http"})
+	assert.True(t, stderrs.As(err, &ie))
+	assert.Equal(t, ie.Kind, "core.portNum")
+
+	// Values that overflow a narrow integer are invalid rather than truncated
+	err = ParseArgs(&cli{}, []string{"--share", "300"})
+	assert.True(t, stderrs.As(err, &ie))
+	assert.Equal(t, ie.Kind, "core.percent")
+}
+
+func TestParse_CountFlag(t *testing.T) {
+	type cli struct {
+		Clifford `name:"app"`
+
+		Verbose struct {
+			Value    int
+			Clifford `short:"v" long:"verbose" count:"true"`
+		}
+		File string
+	}
+
+	for _, tc := range []struct {
+		args []string
+		want int
+	}{
+		{[]string{"in.txt"}, 0},
+		{[]string{"-v", "in.txt"}, 1},
+		{[]string{"-v", "-v", "-v", "in.txt"}, 3},
+		{[]string{"-vvv", "in.txt"}, 3},
+		{[]string{"-vv", "in.txt", "--verbose"}, 3},
+		{[]string{"--verbose=5", "in.txt"}, 5},
+	} {
+		c := cli{}
+		assert.Nil(t, ParseArgs(&c, tc.args))
+		assert.Equal(t, c.Verbose.Value, tc.want)
+		// A count flag never takes the following argument as its value
+		assert.Equal(t, c.File, "in.txt")
+	}
+
+	// A run of a letter that is not a count flag is still unknown
+	var ue clierr.UnknownFlagError
+	assert.True(t, stderrs.As(ParseArgs(&cli{}, []string{"-xxx"}), &ue))
+
+	type invalid struct {
+		Clifford `name:"app"`
+
+		Verbose bool `short:"v" count:"true"`
+	}
+	var de clierr.DefinitionError
+	assert.True(t, stderrs.As(ParseArgs(&invalid{}, []string{"-v"}), &de))
+}
+
+func TestSubcommandHelp_AnyPosition(t *testing.T) {
+	type cli struct {
+		Clifford `name:"app"`
+		Help
+
+		Verbose bool `long:"verbose"`
+		Serve   struct {
+			Subcommand
+			Port struct {
+				Value    int
+				Clifford `long:"port"`
+			}
+			Dir string
+		}
+	}
+
+	for _, tc := range []struct {
+		args  []string
+		usage string
+	}{
+		{[]string{"--help"}, "Usage: app"},
+		{[]string{"--verbose", "--help"}, "Usage: app"},
+		{[]string{"serve", "--help"}, "Usage: app serve"},
+		{[]string{"serve", "--port", "80", "--help"}, "Usage: app serve"},
+		{[]string{"serve", "--help", "--port", "80"}, "Usage: app serve"},
+		{[]string{"serve", "/srv", "-h"}, "Usage: app serve"},
+		{[]string{"--verbose", "serve", "--port", "80", "--help"}, "Usage: app serve"},
+		{[]string{"serve", "--port", "--help"}, "Usage: app serve"},
+	} {
+		var buf bytes.Buffer
+		err := ParseArgs(&cli{}, tc.args, options.WithOutput(&buf), options.WithExitFunc(func(int) {}))
+		assert.True(t, stderrs.Is(err, clierr.ErrHelpRequested))
+		first, _, _ := strings.Cut(buf.String(), "\n")
+		if !strings.HasPrefix(first, tc.usage) || (tc.usage == "Usage: app" && strings.Contains(first, "serve")) {
+			t.Errorf("%v: got usage line %q, want %q", tc.args, first, tc.usage)
+		}
+	}
+
+	// After "--" a help flag is an ordinary argument
+	c := cli{}
+	assert.Nil(t, ParseArgs(&c, []string{"serve", "--", "--help"}))
+	assert.Equal(t, c.Serve.Dir, "--help")
+}
+
+// boolFlagValue is a flag.Value that, like the standard library's boolean
+// flags, takes no separate value.
+type boolFlagValue struct{ on bool }
+
+func (b *boolFlagValue) String() string     { return strconv.FormatBool(b.on) }
+func (b *boolFlagValue) IsBoolFlag() bool   { return true }
+func (b *boolFlagValue) Set(s string) error { v, err := strconv.ParseBool(s); b.on = v; return err }
+
+func TestParse_TypeAwareTokenizer(t *testing.T) {
+	type cli struct {
+		Clifford `name:"app"`
+
+		Verbose struct {
+			Value    bool
+			Clifford `short:"v" long:"verbose"`
+		}
+		Offset struct {
+			Value    int
+			Clifford `long:"offset"`
+		}
+		Trace boolFlagValue `long:"trace"`
+		Input string
+	}
+
+	// A boolean flag leaves the following positional alone
+	c := cli{}
+	assert.Nil(t, ParseArgs(&c, []string{"--verbose", "input.txt"}))
+	assert.True(t, c.Verbose.Value)
+	assert.Equal(t, c.Input, "input.txt")
+
+	c = cli{}
+	assert.Nil(t, ParseArgs(&c, []string{"-v", "input.txt"}))
+	assert.True(t, c.Verbose.Value)
+	assert.Equal(t, c.Input, "input.txt")
+
+	// So does a flag.Value reporting IsBoolFlag
+	c = cli{}
+	assert.Nil(t, ParseArgs(&c, []string{"--trace", "input.txt"}))
+	assert.True(t, c.Trace.on)
+	assert.Equal(t, c.Input, "input.txt")
+
+	// A negative number is the value of a flag that takes one
+	c = cli{}
+	assert.Nil(t, ParseArgs(&c, []string{"--verbose", "--offset", "-3", "input.txt"}))
+	assert.Equal(t, c.Offset.Value, -3)
+	assert.Equal(t, c.Input, "input.txt")
+
+	// A trailing flag that takes a value is reported rather than ignored
+	err := ParseArgs(&cli{}, []string{"input.txt", "--offset"})
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "flag --offset requires a value")
+}
+
+func TestParse_NegatedBoolFlag(t *testing.T) {
+	type cli struct {
+		Clifford `name:"app"`
+
+		Color struct {
+			Value    bool `default:"true"`
+			Clifford `short:"c" long:"color"`
+		}
+		File string
+	}
+
+	for _, tc := range []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"in.txt"}, true},
+		{[]string{"--no-color", "in.txt"}, false},
+		{[]string{"--color", "--no-color", "in.txt"}, false},
+		{[]string{"--no-color", "--color", "in.txt"}, true},
+		{[]string{"--no-color", "-c", "in.txt"}, true},
+	} {
+		c := cli{}
+		assert.Nil(t, ParseArgs(&c, tc.args))
+		assert.Equal(t, c.Color.Value, tc.want)
+		// The --no- form never takes the following argument as its value
+		assert.Equal(t, c.File, "in.txt")
+	}
+}
+
+func TestParse_ExplicitBoolValues(t *testing.T) {
+	type cli struct {
+		Clifford `name:"app"`
+
+		Verbose struct {
+			Value    bool `default:"true"`
+			Clifford `short:"v" long:"verbose"`
+		}
+		Quiet bool `short:"q"`
+		File  string
+	}
+
+	for _, tc := range []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"--verbose", "in.txt"}, true},
+		{[]string{"--verbose=false", "in.txt"}, false},
+		{[]string{"--verbose=0", "in.txt"}, false},
+		{[]string{"--verbose=1", "in.txt"}, true},
+		{[]string{"--verbose", "false", "in.txt"}, false},
+		{[]string{"-v=false", "in.txt"}, false},
+		{[]string{"-v", "true", "in.txt"}, true},
+		{[]string{"--verbose=false", "--verbose", "in.txt"}, true},
+		{[]string{"--verbose", "--verbose=0", "in.txt"}, false},
+	} {
+		c := cli{}
+		assert.Nil(t, ParseArgs(&c, tc.args))
+		assert.Equal(t, c.Verbose.Value, tc.want)
+		assert.Equal(t, c.File, "in.txt")
+	}
+
+	c := cli{}
+	assert.Nil(t, ParseArgs(&c, []string{"-q=true", "in.txt"}))
+	assert.True(t, c.Quiet)
+
+	var ie clierr.InvalidValueError
+	assert.True(t, stderrs.As(ParseArgs(&cli{}, []string{"--verbose=maybe"}), &ie))
+}
+
+func TestParse_BoolFlagKeepsPositional(t *testing.T) {
+	type cli struct {
+		Clifford `name:"mytool"`
+
+		Verbose bool `short:"v" long:"verbose"`
+		Output  struct {
+			Value    string
+			Clifford `long:"output"`
+			Append   bool `long:"append"`
+		}
+		Input string
+	}
+
+	c := cli{}
+	assert.Nil(t, ParseArgs(&c, []string{"--verbose", "input.txt"}))
+	assert.True(t, c.Verbose)
+	assert.Equal(t, c.Input, "input.txt")
+
+	// Booleans declared inside a container keep the positional too
+	c = cli{}
+	assert.Nil(t, ParseArgs(&c, []string{"--output", "out.txt", "--append", "input.txt"}))
+	assert.Equal(t, c.Output.Value, "out.txt")
+	assert.True(t, c.Output.Append)
+	assert.Equal(t, c.Input, "input.txt")
+
+	// And so do booleans of a subcommand
+	type subcli struct {
+		Clifford `name:"mytool"`
+
+		Verbose bool `short:"v" long:"verbose"`
+		Build   struct {
+			Subcommand
+			Force  bool `long:"force"`
+			Target string
+		}
+	}
+	sc := subcli{}
+	assert.Nil(t, ParseArgs(&sc, []string{"-v", "build", "--force", "release"}))
+	assert.True(t, sc.Verbose)
+	assert.True(t, sc.Build.Force)
+	assert.Equal(t, sc.Build.Target, "release")
+}
+
+func TestParse_RequiredCommandTail(t *testing.T) {
+	type cli struct {
+		Clifford `name:"env"`
+
+		Ignore  bool `short:"i"`
+		Assign  string
+		Command []string `rest_required:"true"`
+	}
+
+	c := cli{}
+	assert.Nil(t, ParseArgs(&c, []string{"VAR=1", "--", "echo", "hi"}))
+	assert.Equal(t, c.Assign, "VAR=1")
+	assert.Equal(t, strings.Join(c.Command, " "), "echo hi")
+
+	// Flags after "--" belong to the command
+	c = cli{}
+	assert.Nil(t, ParseArgs(&c, []string{"-i", "--", "ls", "-i"}))
+	assert.True(t, c.Ignore)
+	assert.Equal(t, c.Assign, "")
+	assert.Equal(t, strings.Join(c.Command, " "), "ls -i")
+
+	// The command is mandatory
+	for _, args := range [][]string{{"VAR=1", "--"}, {"VAR=1"}} {
+		var me clierr.MissingArgError
+		assert.True(t, stderrs.As(ParseArgs(&cli{}, args), &me))
+		assert.Equal(t, me.Field, "Command")
+	}
+
+	type invalid struct {
+		Clifford `name:"env"`
+
+		Command string `rest_required:"true"`
+	}
+	var de clierr.DefinitionError
+	assert.True(t, stderrs.As(ParseArgs(&invalid{}, []string{"--", "ls"}), &de))
+}
+
+func TestParse_AssignmentPositionals(t *testing.T) {
+	type cli struct {
+		Clifford `name:"app"`
+
+		Vars    map[string]string `assignment:"true"`
+		Command string
+		Args    []string `rest_positional:"true"`
+	}
+
+	c := cli{}
+	assert.Nil(t, ParseArgs(&c, []string{"FOO=1", "BAR=2", "command"}))
+	assert.Equal(t, len(c.Vars), 2)
+	assert.Equal(t, c.Vars["FOO"], "1")
+	assert.Equal(t, c.Vars["BAR"], "2")
+	assert.Equal(t, c.Command, "command")
+
+	// Without assignments the map stays empty and positionals bind as usual
+	c = cli{}
+	assert.Nil(t, ParseArgs(&c, []string{"command", "a"}))
+	assert.Equal(t, len(c.Vars), 0)
+	assert.Equal(t, c.Command, "command")
+	assert.Equal(t, strings.Join(c.Args, " "), "a")
+
+	// Values keep any later "="
+	c = cli{}
+	assert.Nil(t, ParseArgs(&c, []string{"OPTS=a=b", "command"}))
+	assert.Equal(t, c.Vars["OPTS"], "a=b")
+
+	type invalid struct {
+		Clifford `name:"app"`
+
+		Vars []string `assignment:"true"`
+	}
+	var de clierr.DefinitionError
+	assert.True(t, stderrs.As(ParseArgs(&invalid{}, []string{"A=1"}), &de))
+}
+
+func TestParse_PointerFields(t *testing.T) {
+	type cli struct {
+		Clifford `name:"app"`
+
+		Port struct {
+			Value    *int
+			Clifford `long:"port"`
+		}
+		Name    *string `long:"name"`
+		Verbose *bool   `short:"v"`
+		Level   *int    `long:"level" default:"3" min:"1"`
+		File    string
+	}
+
+	// Flags that are not given leave their pointers nil
+	c := cli{}
+	assert.Nil(t, ParseArgs(&c, []string{"in.txt"}))
+	assert.True(t, c.Port.Value == nil)
+	assert.True(t, c.Name == nil)
+	assert.True(t, c.Verbose == nil)
+	assert.Equal(t, *c.Level, 3)
+
+	// Given flags allocate, even for zero values
+	c = cli{}
+	assert.Nil(t, ParseArgs(&c, []string{"--port", "0", "--name", "", "-v", "in.txt", "--level=5"}))
+	assert.Equal(t, *c.Port.Value, 0)
+	assert.Equal(t, *c.Name, "")
+	assert.True(t, *c.Verbose)
+	assert.Equal(t, *c.Level, 5)
+	assert.Equal(t, c.File, "in.txt")
+
+	var ie clierr.InvalidValueError
+	assert.True(t, stderrs.As(ParseArgs(&cli{}, []string{"--port", "abc"}), &ie))
+	assert.Equal(t, ie.Kind, "int")
+	var re clierr.RangeError
+	assert.True(t, stderrs.As(ParseArgs(&cli{}, []string{"--level", "0"}), &re))
+}
+
+func TestParse_SubcommandAliases(t *testing.T) {
+	type cli struct {
+		Clifford `name:"git"`
+
+		Checkout struct {
+			Subcommand `aliases:"co, ck"`
+			Branch     string
+		}
+		Commit struct {
+			Subcommand `name:"commit" aliases:"ci"`
+			Message    string `short:"m"`
+		}
+	}
+
+	c := cli{}
+	res, err := ParseResult(&c, options.WithArgs([]string{"co", "main"}))
+	assert.Nil(t, err)
+	assert.Equal(t, c.Checkout.Branch, "main")
+	// The canonical name is reported whichever alias was typed
+	assert.Equal(t, res.Report().Command, "checkout")
+
+	c = cli{}
+	assert.Nil(t, ParseArgs(&c, []string{"ci", "-m", "fix"}))
+	assert.Equal(t, c.Commit.Message, "fix")
+
+	// Suggestions consider aliases as well as names
+	var ue clierr.UnknownSubcommandError
+	assert.True(t, stderrs.As(ParseArgs(&cli{}, []string{"cx"}), &ue))
+	assert.True(t, ue.Suggestion == "co" || ue.Suggestion == "ck" || ue.Suggestion == "ci")
+}
+
+func TestParse_RequireSubcommand(t *testing.T) {
+	type cli struct {
+		Clifford `name:"kubectl" require_subcmd:"true"`
+		Help
+
+		Verbose bool `short:"v"`
+		Get     struct {
+			Subcommand
+		}
+		Config struct {
+			Subcommand `require_subcmd:"true"`
+			View       struct {
+				Subcommand
+			}
+		}
+	}
+
+	err := ParseArgs(&cli{}, []string{"-v"})
+	assert.True(t, stderrs.Is(err, clierr.ErrSubcommandRequired))
+	assert.Equal(t, err.Error(), "missing subcommand; available: get, config")
+
+	// Nested commands can require a subcommand of their own
+	var se clierr.SubcommandRequiredError
+	assert.True(t, stderrs.As(ParseArgs(&cli{}, []string{"config"}), &se))
+	assert.Equal(t, se.Command, "config")
+	assert.Equal(t, strings.Join(se.Available, ","), "view")
+
+	assert.Nil(t, ParseArgs(&cli{}, []string{"get"}))
+	assert.Nil(t, ParseArgs(&cli{}, []string{"config", "view"}))
+	// Help is still available on a bare invocation
+	err = ParseArgs(&cli{}, []string{"--help"}, options.WithOutput(&bytes.Buffer{}), options.WithExitFunc(func(int) {}))
+	assert.True(t, stderrs.Is(err, clierr.ErrHelpRequested))
+}
+
+func TestParse_GlobalFlags(t *testing.T) {
+	type cli struct {
+		Clifford `name:"app"`
+
+		Verbose bool   `short:"v" long:"verbose" global:"true"`
+		Config  string `long:"config" persistent:"true"`
+		Level   int    `short:"l" count:"true" global:"true"`
+		Local   string `long:"local"`
+
+		Serve struct {
+			Subcommand
+			Port int    `long:"port"`
+			Name string `long:"config"`
+			File string
+		}
+		Exec struct {
+			Subcommand
+			Command []string `rest_positional:"true"`
+		}
+	}
+
+	c := cli{}
+	assert.Nil(t, ParseArgs(&c, []string{"serve", "--port", "80", "-v", "-ll", "in.txt"}))
+	assert.True(t, c.Verbose)
+	assert.Equal(t, c.Level, 2)
+	assert.Equal(t, c.Serve.Port, 80)
+	assert.Equal(t, c.Serve.File, "in.txt")
+
+	// Globals may appear on either side of the subcommand; the last one wins
+	c = cli{}
+	assert.Nil(t, ParseArgs(&c, []string{"-v", "exec", "--verbose=false", "ls", "-v"}))
+	assert.False(t, c.Verbose)
+	// Arguments captured by a rest positional are left alone
+	assert.Equal(t, strings.Join(c.Exec.Command, " "), "ls -v")
+
+	// A flag the subcommand declares itself belongs to the subcommand
+	c = cli{}
+	assert.Nil(t, ParseArgs(&c, []string{"--config", "a.json", "serve", "--config", "b"}))
+	assert.Equal(t, c.Config, "a.json")
+	assert.Equal(t, c.Serve.Name, "b")
+
+	// Flags that are not global still only belong to the root
+	var ue clierr.UnknownFlagError
+	assert.True(t, stderrs.As(ParseArgs(&cli{}, []string{"serve", "--local", "x"}), &ue))
+}
+
+func TestParse_FlagConstraints(t *testing.T) {
+	type cli struct {
+		Clifford `name:"app"`
+
+		TLSCert struct {
+			Value    string
+			Clifford `long:"tls-cert" requires:"TLSKey"`
+		}
+		TLSKey struct {
+			Value    string
+			Clifford `long:"tls-key" env:"APP_TLS_KEY"`
+		}
+		Insecure bool `long:"insecure" conflicts:"tls-cert"`
+		Quiet    bool `short:"q" conflicts:"Verbose"`
+		Verbose  bool `short:"v"`
+	}
+
+	err := ParseArgs(&cli{}, []string{"--tls-cert", "c.pem"})
+	var ce clierr.FlagConstraintError
+	assert.True(t, stderrs.As(err, &ce))
+	assert.True(t, stderrs.Is(err, clierr.ErrFlagConstraint))
+	assert.Equal(t, err.Error(), "--tls-cert requires --tls-key")
+
+	assert.Nil(t, ParseArgs(&cli{}, []string{"--tls-cert", "c.pem", "--tls-key", "k.pem"}))
+	// A required flag may come from any source
+	t.Setenv("APP_TLS_KEY", "k.pem")
+	assert.Nil(t, ParseArgs(&cli{}, []string{"--tls-cert", "c.pem"}))
+
+	err = ParseArgs(&cli{}, []string{"--insecure", "--tls-cert", "c.pem"})
+	assert.Equal(t, err.Error(), "--insecure conflicts with --tls-cert")
+	err = ParseArgs(&cli{}, []string{"-q", "-v"})
+	assert.Equal(t, err.Error(), "-q conflicts with -v")
+	assert.Nil(t, ParseArgs(&cli{}, []string{"-q"}))
+
+	// A default does not satisfy requires
+	type defaulted struct {
+		Clifford `name:"app"`
+
+		Cert string `long:"cert" requires:"Key"`
+		Key  string `long:"key" default:"server.key"`
+	}
+	err = ParseArgs(&defaulted{}, []string{"--cert", "c.pem"})
+	assert.Equal(t, err.Error(), "--cert requires --key")
+	assert.Nil(t, ParseArgs(&defaulted{}, []string{"--cert", "c.pem", "--key", "k.pem"}))
+}
+
+func TestParse_VariadicPositional(t *testing.T) {
+	type cli struct {
+		Clifford `name:"cat"`
+
+		Number bool `short:"n"`
+		Dest   string
+		Files  struct {
+			Value []string
+			Required
+		}
+	}
+
+	c := cli{}
+	assert.Nil(t, ParseArgs(&c, []string{"out.txt", "a.txt", "-n", "b.txt", "c.txt"}))
+	assert.Equal(t, c.Dest, "out.txt")
+	assert.Equal(t, strings.Join(c.Files.Value, ","), "a.txt,b.txt,c.txt")
+	assert.True(t, c.Number)
+
+	// A required variadic positional needs at least one value
+	var me clierr.MissingArgError
+	assert.True(t, stderrs.As(ParseArgs(&cli{}, []string{"out.txt"}), &me))
+	assert.Equal(t, me.Field, "Files")
+
+	type misplaced struct {
+		Clifford `name:"cp"`
+
+		Sources []string
+		Dest    string
+	}
+	var de clierr.DefinitionError
+	assert.True(t, stderrs.As(ParseArgs(&misplaced{}, []string{"a", "b"}), &de))
+	assert.Equal(t, de.Msg, "variadic positional Sources must be the last positional")
+}
+
+func TestParse_TooManyPositionals(t *testing.T) {
+	type cli struct {
+		Clifford `name:"mv"`
+
+		Force bool `short:"f"`
+		Src   string
+		Dst   string
+	}
+
+	err := ParseArgs(&cli{}, []string{"a", "b", "c"})
+	var te clierr.TooManyArgsError
+	assert.True(t, stderrs.As(err, &te))
+	assert.True(t, stderrs.Is(err, clierr.ErrTooManyArgs))
+	assert.Equal(t, err.Error(), "too many arguments: unexpected c")
+
+	// A forgotten flag name leaves its value over too
+	err = ParseArgs(&cli{}, []string{"a", "b", "-f", "yes"})
+	assert.True(t, stderrs.As(err, &te))
+	assert.Equal(t, strings.Join(te.Extra, ","), "yes")
+
+	assert.Nil(t, ParseArgs(&cli{}, []string{"a", "b"}))
+
+	// A variadic positional absorbs the extras
+	type variadic struct {
+		Clifford `name:"cp"`
+
+		Dst string
+		Src []string
+	}
+	assert.Nil(t, ParseArgs(&variadic{}, []string{"a", "b", "c"}))
+}
+
+func TestParse_AllErrors(t *testing.T) {
+	type cli struct {
+		Clifford `name:"app"`
+
+		File struct {
+			Value string
+			Required
+		}
+		Port  int    `long:"port"`
+		Level string `long:"level" choices:"debug,info"`
+		Name  string `long:"name" required:"true"`
+	}
+
+	args := []string{"--prot", "80", "--port", "abc", "--level", "trace", "--colour"}
+
+	// By default parsing stops at the first problem
+	var ue clierr.UnknownFlagError
+	err := ParseArgs(&cli{}, args)
+	assert.True(t, stderrs.As(err, &ue))
+	assert.Equal(t, ue.Name, "--prot")
+
+	err = ParseArgs(&cli{}, args, options.WithAllErrors())
+	var multi clierr.MultiError
+	assert.True(t, stderrs.As(err, &multi))
+	assert.Equal(t, len(multi.Errors), 6)
+	assert.Equal(t, err.Error(), strings.Join([]string{
+		`unknown flag: --prot (did you mean "--port"?)`,
+		"unknown flag: --colour",
+		"missing required argument: File",
+		`invalid value for Port: "abc" is not a valid int`,
+		`invalid value for Level: "trace" (choices: debug, info)`,
+		"missing required argument: Name",
+	}, "\n"))
+	var ce clierr.InvalidChoiceError
+	assert.True(t, stderrs.As(err, &ce))
+	assert.Equal(t, ce.Value, "trace")
+
+	// A single problem is returned on its own
+	err = ParseArgs(&cli{}, []string{"in.txt"}, options.WithAllErrors())
+	var me clierr.MissingArgError
+	assert.True(t, stderrs.As(err, &me))
+	assert.False(t, stderrs.As(err, &multi))
+}
+
+func TestParse_ValueFromFile(t *testing.T) {
+	dir := t.TempDir()
+	secret := dir + "/token"
+	assert.Nil(t, os.WriteFile(secret, []byte("s3cret\n"), 0o600))
+
+	type cli struct {
+		Clifford `name:"app"`
+
+		Token struct {
+			Value    string
+			Clifford `long:"token" fromfile:"true"`
+		}
+		Handle string `long:"handle"`
+	}
+
+	c := cli{}
+	assert.Nil(t, ParseArgs(&c, []string{"--token", "@" + secret, "--handle", "@me"}))
+	assert.Equal(t, c.Token.Value, "s3cret")
+	// Values of fields without the tag keep their @
+	assert.Equal(t, c.Handle, "@me")
+
+	c = cli{}
+	assert.Nil(t, ParseArgs(&c, []string{"--token", "plain"}))
+	assert.Equal(t, c.Token.Value, "plain")
+
+	var ie clierr.InvalidValueError
+	assert.True(t, stderrs.As(ParseArgs(&cli{}, []string{"--token", "@" + dir + "/missing"}), &ie))
+	assert.Equal(t, ie.Field, "Token")
+	assert.Equal(t, ie.Kind, "readable file")
+}
+
+func TestParse_HiddenFields(t *testing.T) {
+	type cli struct {
+		Clifford `name:"app"`
+
+		Trace struct {
+			Value    bool
+			Clifford `long:"trace-internals" hidden:"true"`
+		}
+		Serve struct {
+			Subcommand
+		}
+		Debug struct {
+			Subcommand `hidden:"true"`
+		}
+	}
+
+	c := &cli{}
+	assert.Nil(t, ParseArgs(c, []string{"--trace-internals", "debug"}))
+	assert.True(t, c.Trace.Value)
+
+	// Hidden subcommands are never suggested or listed
+	err := New(options.WithAvailableCommands(), options.WithArgs([]string{"debgu"})).Parse(&cli{})
+	var ue clierr.UnknownSubcommandError
+	assert.True(t, stderrs.As(err, &ue))
+	assert.Equal(t, ue.Suggestion, "")
+	assert.Equal(t, strings.Join(ue.Available, ","), "serve")
+}
@@ -0,0 +1,21 @@
+package core
+
+import "github.com/chriso345/clifford/internal/options"
+
+// Parser parses command lines with a fixed set of options. Everything a parse
+// depends on, from the arguments to the output writer and exit function, is
+// carried by the Parser, so independent Parsers can be used concurrently.
+type Parser struct {
+	opts []options.Option
+}
+
+// New returns a Parser configured by opts.
+func New(opts ...options.Option) *Parser {
+	return &Parser{opts: append([]options.Option{}, opts...)}
+}
+
+// Parse parses the arguments given by WithArgs, or os.Args, into target.
+func (p *Parser) Parse(target any) error {
+	s := newParseState(p.opts)
+	return parse(target, s.args(), s)
+}
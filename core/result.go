@@ -0,0 +1,93 @@
+package core
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Result describes a completed parse. Fields are addressed by dotted paths of
+// Go field names relative to the root struct, such as "Serve.Port".
+type Result struct {
+	// Warnings lists the conversion failures tolerated by WithBestEffort, in
+	// the order they occurred.
+	Warnings []string
+
+	target   reflect.Value   // the parsed root struct
+	set      map[string]bool // paths of the fields that received a value
+	explicit map[string]bool // paths of the fields given on the command line
+	report   Report
+}
+
+// Report summarizes how an invocation was interpreted, for tools that log it,
+// such as CI jobs.
+type Report struct {
+	// Command is the space-separated path of the subcommand that was run,
+	// such as "remote add", or empty for the root command.
+	Command string
+
+	// Bound lists every field that received a value, in the order they were
+	// bound, with the source each value came from.
+	Bound []BoundValue
+
+	// Warnings lists the conversion failures tolerated by WithBestEffort.
+	Warnings []string
+
+	// Duration is the time taken by the parse.
+	Duration time.Duration
+}
+
+// BoundValue records the value a single field received and its source. The
+// entries of slice and map fields are joined by spaces in Value.
+type BoundValue struct {
+	Path   string // dotted field path relative to the root struct, such as "Serve.Port"
+	Value  string // the raw value, masked as "****" for fields tagged `secret`
+	Source string // "command line", "$NAME" for an environment variable, "stdin", "config" or "default"
+}
+
+// Report returns the summary of the parse.
+func (r *Result) Report() Report {
+	return r.report
+}
+
+// Get returns the value bound to the field at path and whether a value was
+// set from any source, including defaults. Flag and argument containers
+// resolve to their Value field and subcommands to their Subcommand marker.
+// It returns nil and false when path does not name a field.
+func (r *Result) Get(path string) (any, bool) {
+	v, ok := r.resolve(path)
+	if !ok {
+		return nil, false
+	}
+	return v.Interface(), r.set[path]
+}
+
+// IsSet reports whether the field at path was given on the command line, as
+// opposed to taking its value from the environment, stdin, a config file or a
+// default, or not receiving one at all. A subcommand is set when it was run.
+func (r *Result) IsSet(path string) bool {
+	return r.explicit[path]
+}
+
+// resolve walks path from the root struct to the value it names.
+func (r *Result) resolve(path string) (reflect.Value, bool) {
+	v := r.target
+	for _, name := range strings.Split(path, ".") {
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, false
+		}
+		field, ok := v.Type().FieldByName(name)
+		if !ok || !field.IsExported() {
+			return reflect.Value{}, false
+		}
+		v = v.FieldByIndex(field.Index)
+	}
+	if v.Kind() == reflect.Struct {
+		if value := v.FieldByName("Value"); value.IsValid() {
+			v = value
+		} else if marker := v.FieldByName("Subcommand"); marker.IsValid() {
+			v = marker
+		}
+	}
+	return v, v.CanInterface()
+}
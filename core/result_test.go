@@ -0,0 +1,174 @@
+package core
+
+import (
+	"os"
+	"testing"
+
+	"github.com/chriso345/clifford/internal/options"
+	"github.com/chriso345/gore/assert"
+)
+
+type resultCLI struct {
+	Clifford `name:"app"`
+
+	Serve struct {
+		Subcommand
+		Debug bool `long:"debug"`
+		Port  struct {
+			Value    int
+			Clifford `long:"port"`
+		}
+		Host struct {
+			Value    string `default:"localhost"`
+			Clifford `long:"host"`
+		}
+		TLS struct {
+			Value    bool
+			Clifford `long:"tls"`
+		}
+	}
+}
+
+func TestParseResult_GetByPath(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	os.Args = []string{"app", "serve", "--debug", "--port", "8080"}
+
+	cli := resultCLI{}
+	res, err := ParseResult(&cli)
+	assert.Nil(t, err)
+
+	port, ok := res.Get("Serve.Port")
+	assert.True(t, ok)
+	assert.Equal(t, port.(int), 8080)
+
+	host, ok := res.Get("Serve.Host")
+	assert.True(t, ok)
+	assert.Equal(t, host.(string), "localhost")
+
+	debug, ok := res.Get("Serve.Debug")
+	assert.True(t, ok)
+	assert.True(t, debug.(bool))
+
+	serve, ok := res.Get("Serve")
+	assert.True(t, ok)
+	assert.True(t, bool(serve.(Subcommand)))
+
+	// Declared but never given
+	tls, ok := res.Get("Serve.TLS")
+	assert.False(t, ok)
+	assert.False(t, tls.(bool))
+
+	// Unknown paths resolve to nothing
+	missing, ok := res.Get("Serve.Nope")
+	assert.False(t, ok)
+	assert.Nil(t, missing)
+}
+
+func TestParseResult_BestEffortWarnings(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	type cli struct {
+		Clifford `name:"app"`
+
+		Port struct {
+			Value    uint `default:"8080"`
+			Clifford `long:"port"`
+		}
+		Ratio float64 `long:"ratio"`
+		Name  string  `long:"name"`
+	}
+
+	os.Args = []string{"app", "--port", "abc", "--ratio", "half", "--name", "srv"}
+	c := cli{}
+	res, err := ParseResult(&c, options.WithBestEffort())
+	assert.Nil(t, err)
+	assert.Equal(t, c.Port.Value, uint(8080))
+	assert.Equal(t, c.Ratio, 0.0)
+	assert.Equal(t, c.Name, "srv")
+	assert.Equal(t, len(res.Warnings), 2)
+	assert.StringContains(t, res.Warnings[0], "Port")
+	assert.StringContains(t, res.Warnings[1], "Ratio")
+
+	// Without best effort the same input fails
+	c = cli{}
+	_, err = ParseResult(&c)
+	assert.NotNil(t, err)
+}
+
+func TestParseResult_Report(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	os.Args = []string{"app", "serve", "--port", "8080"}
+
+	cli := resultCLI{}
+	res, err := ParseResult(&cli)
+	assert.Nil(t, err)
+
+	report := res.Report()
+	assert.Equal(t, report.Command, "serve")
+	assert.Equal(t, len(report.Bound), 2)
+	assert.Equal(t, report.Bound[0], BoundValue{Path: "Serve.Port", Value: "8080", Source: "command line"})
+	assert.Equal(t, report.Bound[1], BoundValue{Path: "Serve.Host", Value: "localhost", Source: "default"})
+	assert.Equal(t, len(report.Warnings), 0)
+	assert.True(t, report.Duration > 0)
+}
+
+func TestParseResult_ReportCollectedValues(t *testing.T) {
+	type cli struct {
+		Clifford `name:"app"`
+
+		Tags   []string          `long:"tag"`
+		Labels map[string]string `long:"label"`
+		Files  []string
+	}
+
+	res, err := ParseResult(&cli{}, options.WithArgs([]string{"--tag", "a", "--label", "k=v", "--tag", "b", "--label", "x=y", "f1", "f2"}))
+	assert.Nil(t, err)
+	report := res.Report()
+	assert.Equal(t, report.Bound[0], BoundValue{Path: "Tags", Value: "a b", Source: "command line"})
+	assert.Equal(t, report.Bound[1], BoundValue{Path: "Labels", Value: "k=v x=y", Source: "command line"})
+	assert.Equal(t, report.Bound[2], BoundValue{Path: "Files", Value: "f1 f2", Source: "command line"})
+}
+
+func TestParseResult_IsSet(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+
+	type cli struct {
+		Clifford `name:"app"`
+
+		Port struct {
+			Value    int `default:"8080"`
+			Clifford `long:"port"`
+		}
+		Token string `long:"token" env:"APP_TOKEN"`
+		Serve struct {
+			Subcommand
+			Debug bool `long:"debug"`
+			Dir   string
+		}
+	}
+	t.Setenv("APP_TOKEN", "secret")
+
+	// Defaults and the environment do not count as set
+	os.Args = []string{"app", "serve", "--debug"}
+	res, err := ParseResult(&cli{})
+	assert.Nil(t, err)
+	assert.False(t, res.IsSet("Port"))
+	assert.False(t, res.IsSet("Token"))
+	assert.True(t, res.IsSet("Serve"))
+	assert.True(t, res.IsSet("Serve.Debug"))
+	assert.False(t, res.IsSet("Serve.Dir"))
+	_, ok := res.Get("Port")
+	assert.True(t, ok)
+
+	// A flag given with its default value still counts
+	os.Args = []string{"app", "--port", "8080", "--token", "abc"}
+	res, err = ParseResult(&cli{})
+	assert.Nil(t, err)
+	assert.True(t, res.IsSet("Port"))
+	assert.True(t, res.IsSet("Token"))
+	assert.False(t, res.IsSet("Serve"))
+}
@@ -0,0 +1,41 @@
+package core
+
+import (
+	"context"
+
+	"github.com/chriso345/clifford/internal/options"
+)
+
+// Runner is implemented by command structs that handle their own invocation.
+// ParseAndRun calls Run on the command the user invoked.
+type Runner interface {
+	Run(ctx context.Context) error
+}
+
+// ParseAndRun parses os.Args into target like Parse, then calls Run on the
+// deepest command given on the command line that implements Runner, falling
+// back to its parents and finally the root. Commands that were not invoked
+// never run. The context passed to Run is the one given by WithContext, or
+// context.Background.
+func ParseAndRun(target any, opts ...options.Option) error {
+	return New(opts...).ParseAndRun(target)
+}
+
+// ParseAndRun parses into target like Parse and calls Run on the invoked
+// command. See the ParseAndRun function.
+func (p *Parser) ParseAndRun(target any) error {
+	s := newParseState(p.opts)
+	if err := parse(target, s.args(), s); err != nil || s.finished {
+		return err
+	}
+	ctx := s.cfg.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	for i := len(s.commands) - 1; i >= 0; i-- {
+		if r, ok := s.commands[i].(Runner); ok {
+			return r.Run(ctx)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	stderrs "errors"
+	"testing"
+
+	clierr "github.com/chriso345/clifford/errors"
+	"github.com/chriso345/clifford/internal/options"
+	"github.com/chriso345/gore/assert"
+)
+
+type ctxKey struct{}
+
+// ran records which Run method was called last.
+var ran string
+
+type runServe struct {
+	Subcommand
+	Port int `long:"port"`
+}
+
+func (c *runServe) Run(ctx context.Context) error {
+	ran = "serve " + ctx.Value(ctxKey{}).(string)
+	return nil
+}
+
+type runAdd struct {
+	Subcommand
+}
+
+func (c *runAdd) Run(context.Context) error {
+	ran = "remote add"
+	return nil
+}
+
+type runRemote struct {
+	Subcommand
+	Add  runAdd
+	List struct {
+		Subcommand
+	}
+}
+
+func (c *runRemote) Run(context.Context) error {
+	ran = "remote"
+	return nil
+}
+
+type runCLI struct {
+	Clifford `name:"app"`
+	Help
+
+	Serve  runServe
+	Remote runRemote
+	Fail   struct {
+		Subcommand
+	}
+}
+
+func (c *runCLI) Run(context.Context) error {
+	ran = "root"
+	return stderrs.New("root failed")
+}
+
+func TestParseAndRun(t *testing.T) {
+	ctx := options.WithContext(context.WithValue(context.Background(), ctxKey{}, "ctx"))
+
+	c := &runCLI{}
+	assert.Nil(t, ParseAndRun(c, ctx, options.WithArgs([]string{"serve", "--port", "80"})))
+	assert.Equal(t, ran, "serve ctx")
+	assert.Equal(t, c.Serve.Port, 80)
+
+	// Only the deepest invoked command runs
+	assert.Nil(t, ParseAndRun(&runCLI{}, options.WithArgs([]string{"remote", "add"})))
+	assert.Equal(t, ran, "remote add")
+
+	// A command without Run falls back to its nearest parent that has one
+	assert.Nil(t, ParseAndRun(&runCLI{}, options.WithArgs([]string{"remote", "list"})))
+	assert.Equal(t, ran, "remote")
+
+	err := ParseAndRun(&runCLI{}, options.WithArgs([]string{"fail"}))
+	assert.Equal(t, err.Error(), "root failed")
+	assert.Equal(t, ran, "root")
+
+	// Nothing runs when parsing fails or help is printed
+	ran = ""
+	var ue clierr.UnknownSubcommandError
+	assert.True(t, stderrs.As(ParseAndRun(&runCLI{}, options.WithArgs([]string{"srve"})), &ue))
+	var buf bytes.Buffer
+	p := New(options.WithArgs([]string{"--help"}), options.WithOutput(&buf), options.WithExitFunc(func(int) {}))
+	assert.True(t, stderrs.Is(p.ParseAndRun(&runCLI{}), clierr.ErrHelpRequested))
+	assert.Equal(t, ran, "")
+}
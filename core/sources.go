@@ -0,0 +1,238 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/chriso345/clifford/errors"
+	"github.com/chriso345/clifford/internal/common"
+	"github.com/chriso345/clifford/internal/options"
+)
+
+// readJSONStdin decodes the JSON object piped to stdin into string values
+// keyed by long flag name. It returns nil when stdin is a terminal or empty.
+func readJSONStdin(cfg *options.Config) (map[string]string, error) {
+	r := cfg.Stdin
+	if r == nil {
+		r = os.Stdin
+	}
+	if f, ok := r.(*os.File); ok {
+		// Never block waiting on an interactive terminal
+		info, err := f.Stat()
+		if err != nil || info.Mode()&os.ModeCharDevice != 0 {
+			return nil, nil
+		}
+	}
+
+	return decodeJSONValues(r, "on stdin")
+}
+
+// readConfigFiles decodes each JSON config file in paths, in order, merging
+// their values so that later files override earlier ones. Missing files are
+// skipped.
+func readConfigFiles(paths []string) (map[string]string, error) {
+	values := map[string]string{}
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.NewParseError("reading config file: " + err.Error())
+		}
+		fileValues, err := decodeJSONValues(f, "in "+path)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+		for key, val := range fileValues {
+			values[key] = val
+		}
+	}
+	return values, nil
+}
+
+// decodeJSONValues decodes a JSON object from r into string values keyed by
+// long flag name. origin describes the input in error messages, e.g.
+// "on stdin". An empty input yields no values.
+func decodeJSONValues(r io.Reader, origin string) (map[string]string, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var raw map[string]any
+	if err := dec.Decode(&raw); err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+		return nil, errors.NewParseError(fmt.Sprintf("invalid JSON %s: %s", origin, err))
+	}
+
+	values := make(map[string]string, len(raw))
+	for key, val := range raw {
+		switch v := val.(type) {
+		case string:
+			values[key] = v
+		case json.Number, bool:
+			values[key] = fmt.Sprint(v)
+		case nil:
+			// null leaves the field to lower-precedence sources
+		default:
+			return nil, errors.NewParseError(fmt.Sprintf("unsupported JSON value for %q %s", key, origin))
+		}
+	}
+	return values, nil
+}
+
+// flagsFileFlag is the built-in flag enabled by WithFlagsFile.
+const flagsFileFlag = "--flags-file"
+
+// expandFlagsFile replaces each `--flags-file path` in args with the flags read
+// from path. They are spliced in at the start of the command the flag was
+// given to, so every flag typed on the command line for that command takes
+// precedence over them.
+func expandFlagsFile(target any, args []string) ([]string, error) {
+	t := common.GetStructType(target)
+	out := make([]string, 0, len(args))
+	start := 0 // index in out where the current command's arguments begin
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			out = append(out, args[i:]...)
+			break
+		}
+		path, isFlagsFile := strings.CutPrefix(arg, flagsFileFlag+"=")
+		if arg == flagsFileFlag {
+			if i+1 >= len(args) {
+				return nil, errors.NewParseError(fmt.Sprintf("flag %s requires a value", flagsFileFlag))
+			}
+			i++
+			path, isFlagsFile = args[i], true
+		}
+		if !isFlagsFile {
+			if sub, ok := subcommandType(t, arg); ok {
+				t, start = sub, len(out)+1
+			}
+			out = append(out, arg)
+			continue
+		}
+		tokens, err := readFlagsFile(path)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out[:start], append(tokens, out[start:]...)...)
+		start += len(tokens)
+	}
+	return out, nil
+}
+
+// readFlagsFile reads the flags in the file at path, one `--flag value` per
+// line. Blank lines and lines starting with # are ignored.
+func readFlagsFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.NewParseError("reading flags file: " + err.Error())
+	}
+	var tokens []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		flag, value, hasValue := strings.Cut(line, " ")
+		tokens = append(tokens, flag)
+		if value = strings.TrimSpace(value); hasValue && value != "" {
+			tokens = append(tokens, value)
+		}
+	}
+	return tokens, nil
+}
+
+// fileValue returns the contents of the file named by a value such as
+// `@/run/secrets/token`, without its trailing newline, when b is tagged
+// `fromfile:"true"`. Other values are returned unchanged. A file that cannot
+// be read is an InvalidValueError.
+func fileValue(b binding, value string) (string, error) {
+	path, ok := strings.CutPrefix(value, "@")
+	if !ok || b.tags["fromfile"] != "true" {
+		return value, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		debugf("read %s for %s: %v", path, b.name, err)
+		return "", errors.NewInvalidValue(b.name, value, "readable file")
+	}
+	contents := strings.TrimSuffix(string(data), "\n")
+	return strings.TrimSuffix(contents, "\r"), nil
+}
+
+// loadSources reads the value sources enabled in the configuration.
+func (s *parseState) loadSources() error {
+	if s.cfg.JSONStdin {
+		values, err := readJSONStdin(s.cfg)
+		if err != nil {
+			return err
+		}
+		s.stdinValues = values
+	}
+	if len(s.cfg.ConfigFiles) > 0 {
+		values, err := readConfigFiles(s.cfg.ConfigFiles)
+		if err != nil {
+			return err
+		}
+		s.configValues = values
+	}
+	return nil
+}
+
+// fallback resolves a value for b from the sources below the command line, in
+// precedence order: the `env` variable, JSON on stdin, config files, then the
+// declared default. source names where the value came from.
+func (s *parseState) fallback(b binding) (value, source string, found bool) {
+	if val, ok := envValue(b); ok {
+		return val, "$" + b.tags["env"], true
+	}
+	if long := b.tags["long"]; long != "" {
+		if val, ok := s.stdinValues[long]; ok {
+			return val, "stdin", true
+		}
+		if val, ok := s.configValues[long]; ok {
+			return val, "config", true
+		}
+	}
+	if d := b.tags["default"]; d != "" {
+		return d, sourceDefault, true
+	}
+	return "", "", false
+}
+
+// envValue reads the environment variable named by the `env` tag of b. An
+// empty variable counts as unset, except for booleans, where it means false.
+func envValue(b binding) (string, bool) {
+	name := b.tags["env"]
+	if name == "" {
+		return "", false
+	}
+	val, ok := os.LookupEnv(name)
+	if !ok {
+		return "", false
+	}
+	if b.isBool() {
+		return envBool(val), true
+	}
+	return val, val != ""
+}
+
+// envBool maps the usual spellings of an on/off environment variable, such as
+// 1, yes and on or 0, no, off and empty, to "true" or "false". Other values
+// are returned unchanged.
+func envBool(val string) string {
+	switch strings.ToLower(strings.TrimSpace(val)) {
+	case "1", "t", "true", "y", "yes", "on":
+		return "true"
+	case "", "0", "f", "false", "n", "no", "off":
+		return "false"
+	}
+	return val
+}
@@ -0,0 +1,99 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/chriso345/clifford/errors"
+	"github.com/chriso345/clifford/internal/common"
+)
+
+// helpModes lists the values accepted by the `type` and `help` tags of a Help
+// embedding. An empty value means the default, "flag".
+var helpModes = map[string]bool{"": true, "flag": true, "subcmd": true, "both": true}
+
+// Validate checks the CLI definition target without parsing any arguments,
+// returning a DefinitionError for the first mistake found. The root and every
+// subcommand are checked for unknown help modes, malformed positionals,
+// `min` or `max` bounds that do not parse, patterns that do not compile and
+// `requires` or `conflicts` tags naming flags the command does not declare.
+func Validate(target any) error {
+	if !common.IsStructPtr(target) {
+		return errors.NewParseError("invalid type: must pass pointer to struct")
+	}
+	t := common.GetStructType(target)
+	if !common.HasCliffordField(t) {
+		return errors.NewDefinitionError("root struct must embed clifford.Clifford")
+	}
+	return validateCommand(t, "")
+}
+
+// validateCommand checks the command struct t and, recursively, its
+// subcommands. command is the subcommand path used in error messages.
+func validateCommand(t reflect.Type, command string) error {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if field.Type.Name() != "Help" {
+			continue
+		}
+		mode := field.Tag.Get("help")
+		if mode == "" {
+			mode = field.Tag.Get("type")
+		}
+		if !helpModes[mode] {
+			return errors.NewDefinitionError(fmt.Sprintf("invalid help mode %q%s; expected flag, subcmd or both", mode, inCommand(command)))
+		}
+	}
+
+	bindings := collectBindings(reflect.New(t).Elem())
+	if _, err := newPositionalQueue(nil, bindings); err != nil {
+		return err
+	}
+	for _, b := range bindings {
+		if err := checkBounds(b); err != nil {
+			return err
+		}
+		if _, err := compilePattern(b); err != nil {
+			return err
+		}
+		for _, key := range []string{"requires", "conflicts"} {
+			for _, name := range tagList(b.tags[key]) {
+				if _, ok := resolveBinding(bindings, name); !ok {
+					return errors.NewDefinitionError(fmt.Sprintf("field %s %s unknown flag %q%s", b.name, key, name, inCommand(command)))
+				}
+			}
+		}
+	}
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if field.Type.Kind() != reflect.Struct {
+			continue
+		}
+		tags := common.GetTagsFromEmbedded(field.Type, field.Name)
+		if tags["subcmd"] != "true" {
+			continue
+		}
+		name := tags["name"]
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		if command != "" {
+			name = command + " " + name
+		}
+		if err := validateCommand(field.Type, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// inCommand names the subcommand at path for an error message, or returns an
+// empty string for the root command.
+func inCommand(path string) string {
+	if path == "" {
+		return ""
+	}
+	return fmt.Sprintf(" in subcommand %q", path)
+}
@@ -0,0 +1,68 @@
+package core
+
+import (
+	stderrs "errors"
+	"strings"
+	"testing"
+
+	clierr "github.com/chriso345/clifford/errors"
+	"github.com/chriso345/gore/assert"
+)
+
+func TestValidate_HelpMode(t *testing.T) {
+	type valid struct {
+		Clifford `name:"app"`
+		Help     `type:"both"`
+
+		Serve struct {
+			Subcommand
+			Help `type:"subcmd"`
+		}
+	}
+	assert.Nil(t, Validate(&valid{}))
+
+	type typo struct {
+		Clifford `name:"app"`
+		Help     `type:"flagg"`
+	}
+	err := Validate(&typo{})
+	var de clierr.DefinitionError
+	assert.True(t, stderrs.As(err, &de))
+	assert.True(t, strings.Contains(err.Error(), `"flagg"`))
+
+	type subTypo struct {
+		Clifford `name:"app"`
+
+		Serve struct {
+			Subcommand
+			Help `help:"subcommand"`
+		}
+	}
+	err = Validate(&subTypo{})
+	assert.True(t, stderrs.As(err, &de))
+	assert.True(t, strings.Contains(err.Error(), `subcommand "serve"`))
+}
+
+func TestValidate_Positionals(t *testing.T) {
+	type cli struct {
+		Clifford `name:"app"`
+
+		Src string `pos:"1"`
+		Dst string `pos:"1"`
+	}
+	var de clierr.DefinitionError
+	assert.True(t, stderrs.As(Validate(&cli{}), &de))
+}
+
+func TestValidate_UnknownConstraint(t *testing.T) {
+	type cli struct {
+		Clifford `name:"app"`
+
+		Cert string `long:"cert" requires:"Key"`
+	}
+
+	err := Validate(&cli{})
+	var de clierr.DefinitionError
+	assert.True(t, stderrs.As(err, &de))
+	assert.Equal(t, err.Error(), `invalid CLI definition: field Cert requires unknown flag "Key"`)
+}
@@ -1,10 +1,22 @@
 package display
 
-import "strings"
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"unicode/utf8"
 
-// ansiHelp formats the text with ANSI escape codes for styling.
-func ansiHelp(text string, format ...ansiFormat) string {
-	if len(format) == 0 {
+	"github.com/chriso345/clifford/internal/options"
+)
+
+// ansiHelp formats the text with ANSI escape codes for styling. Text is
+// returned plain when WithColor(false) is given or, following the
+// no-color.org convention, when NO_COLOR is set.
+func ansiHelp(cfg *options.Config, text string, format ...ansiFormat) string {
+	styled := !noColor() && (cfg == nil || cfg.Color == nil || *cfg.Color)
+	if len(format) == 0 || !styled {
 		return text
 	}
 	var builder strings.Builder
@@ -24,3 +36,55 @@ const (
 	ansiBold      ansiFormat = "\033[1m"
 	ansiUnderline ansiFormat = "\033[4m"
 )
+
+// noColor reports whether the NO_COLOR environment variable is set, to any value.
+func noColor() bool {
+	_, ok := os.LookupEnv("NO_COLOR")
+	return ok
+}
+
+// ansiEscape matches the SGR escape sequences emitted by ansiHelp.
+var ansiEscape = regexp.MustCompile("\033\\[[0-9;]*m")
+
+// stripANSI removes ANSI styling from text.
+func stripANSI(text string) string {
+	return ansiEscape.ReplaceAllString(text, "")
+}
+
+// WriteText writes text to w followed by a newline. ANSI styling is only kept
+// when w is a terminal, so output redirected to a file or pager is plain.
+// WithColor overrides the terminal check either way.
+func WriteText(w io.Writer, text string, opts ...options.Option) error {
+	styled := isTerminal(w)
+	if color := options.New(opts...).Color; color != nil {
+		styled = *color
+	}
+	if !styled {
+		text = stripANSI(text)
+	}
+	_, err := fmt.Fprintln(w, text)
+	return err
+}
+
+// isTerminal reports whether w is a character device such as an interactive
+// terminal. Writers that are not files are never terminals.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// visibleLen returns the number of characters text occupies on screen,
+// ignoring ANSI styling, so columns align whether or not color is used.
+func visibleLen(text string) int {
+	return utf8.RuneCountInString(stripANSI(text))
+}
+
+// padVisible pads text with spaces up to width visible characters. Text that
+// is already wider is returned unchanged.
+func padVisible(text string, width int) string {
+	return text + strings.Repeat(" ", max(0, width-visibleLen(text)))
+}
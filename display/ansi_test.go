@@ -1,15 +1,17 @@
 package display
 
 import (
+	"bytes"
 	"strings"
 	"testing"
 
+	"github.com/chriso345/clifford/internal/options"
 	"github.com/chriso345/gore/assert"
 )
 
 func TestAnsiHelp_NoFormat(t *testing.T) {
 	input := "Hello, World!"
-	output := ansiHelp(input)
+	output := ansiHelp(nil, input)
 	assert.Equal(t, output, input)
 }
 
@@ -18,7 +20,7 @@ func TestAnsiHelp_SingleFormat(t *testing.T) {
 	expectedPrefix := string(ansiBold)
 	expectedSuffix := string(ansiReset)
 
-	output := ansiHelp(input, ansiBold)
+	output := ansiHelp(nil, input, ansiBold)
 
 	if !strings.HasPrefix(output, expectedPrefix) {
 		t.Errorf("output does not start with expected ANSI code %q: got %q", expectedPrefix, output)
@@ -31,7 +33,7 @@ func TestAnsiHelp_SingleFormat(t *testing.T) {
 
 func TestAnsiHelp_MultipleFormats(t *testing.T) {
 	input := "Test"
-	output := ansiHelp(input, ansiBold, ansiUnderline)
+	output := ansiHelp(nil, input, ansiBold, ansiUnderline)
 
 	// Expect output to start with concatenation of ansiBold + ansiUnderline
 	expectedPrefix := string(ansiBold) + string(ansiUnderline)
@@ -43,3 +45,36 @@ func TestAnsiHelp_MultipleFormats(t *testing.T) {
 	}
 	assert.StringContains(t, output, input)
 }
+
+func TestPadVisible_AlignsStyledAndPlainText(t *testing.T) {
+	styled := "  " + padVisible(ansiHelp(nil, "--verbose", ansiBold), 12) + "  Enable verbose output"
+	plain := "  " + padVisible("--port", 12) + "  Port to listen on"
+
+	// Both descriptions start at the same on-screen column
+	assert.Equal(t, strings.Index(stripANSI(styled), "Enable"), strings.Index(plain, "Port to"))
+	assert.Equal(t, visibleLen(ansiHelp(nil, "héllo", ansiBold, ansiUnderline)), 5)
+
+	// Text wider than the column is left as is
+	assert.Equal(t, padVisible("--a-very-long-flag", 4), "--a-very-long-flag")
+}
+
+func TestAnsiHelp_NoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	assert.Equal(t, ansiHelp(nil, "Usage:", ansiBold, ansiUnderline), "Usage:")
+}
+
+func TestWriteText_StripsStylingForNonTerminals(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Nil(t, WriteText(&buf, ansiHelp(nil, "Usage:", ansiBold)+" app"))
+	assert.Equal(t, buf.String(), "Usage: app\n")
+}
+
+func TestAnsiHelp_ColorOption(t *testing.T) {
+	off := options.New(options.WithColor(false))
+	assert.Equal(t, ansiHelp(off, "Usage:", ansiBold), "Usage:")
+
+	// NO_COLOR still wins over forced color
+	t.Setenv("NO_COLOR", "1")
+	on := options.New(options.WithColor(true))
+	assert.Equal(t, ansiHelp(on, "Usage:", ansiBold), "Usage:")
+}
@@ -0,0 +1,207 @@
+package display
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/chriso345/clifford/errors"
+	"github.com/chriso345/clifford/internal/common"
+)
+
+// completionCommand is a single command level of the completion tree.
+type completionCommand struct {
+	path     string // slash-separated subcommand path, "" for the root
+	flags    []string
+	subs     []string // subcommand names, each followed by its aliases
+	aliases  []string // other names of this command
+	complete string   // completion for positionals: "file", "dir" or ""
+	children []completionCommand
+}
+
+// GenBashCompletion writes a bash completion script for the CLI defined by target to w.
+//
+// The script completes subcommand names and flags for the command the cursor is in,
+// following nested subcommands. Positional arguments tagged `complete:"file"` or
+// `complete:"dir"` complete file or directory paths.
+func GenBashCompletion(target any, w io.Writer) error {
+	if !common.IsStructPtr(target) {
+		return errors.NewParseError("invalid type: must pass pointer to struct")
+	}
+
+	t := common.GetStructType(target)
+	name := ""
+	for i := range t.NumField() {
+		if field := t.Field(i); field.Type.Name() == "Clifford" {
+			name = field.Tag.Get("name")
+			break
+		}
+	}
+	if name == "" {
+		name = filepath.Base(os.Args[0])
+	}
+	fn := "_" + regexp.MustCompile(`[^A-Za-z0-9_]`).ReplaceAllString(name, "_") + "_completion"
+
+	root := completionTree(t, "", true)
+	var commands []completionCommand
+	var walk func(c completionCommand)
+	walk = func(c completionCommand) {
+		commands = append(commands, c)
+		for _, child := range c.children {
+			walk(child)
+		}
+	}
+	walk(root)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# bash completion for %s\n", name)
+	fmt.Fprintf(&b, "%s() {\n", fn)
+	b.WriteString("    local cur path i\n")
+	b.WriteString("    COMPREPLY=()\n")
+	b.WriteString("    cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	b.WriteString("    path=\"\"\n")
+
+	// Track the subcommand path from the words before the cursor
+	var paths []string
+	for _, c := range commands[1:] {
+		paths = append(paths, c.path)
+	}
+	if len(paths) > 0 {
+		b.WriteString("    for ((i = 1; i < COMP_CWORD; i++)); do\n")
+		b.WriteString("        case \"${path}/${COMP_WORDS[i]}\" in\n")
+		fmt.Fprintf(&b, "            %s) path=\"${path}/${COMP_WORDS[i]}\" ;;\n", strings.Join(paths, "|"))
+		// An alias continues along the path of the command it names
+		for _, c := range commands[1:] {
+			parent := c.path[:strings.LastIndex(c.path, "/")]
+			for _, alias := range c.aliases {
+				fmt.Fprintf(&b, "            %s/%s) path=\"%s\" ;;\n", parent, alias, c.path)
+			}
+		}
+		b.WriteString("        esac\n")
+		b.WriteString("    done\n")
+	}
+
+	b.WriteString("    case \"${path}\" in\n")
+	for _, c := range commands {
+		label := c.path
+		if label == "" {
+			label = `""`
+		}
+		fmt.Fprintf(&b, "        %s)\n", label)
+		b.WriteString("            if [[ \"${cur}\" == -* ]]; then\n")
+		fmt.Fprintf(&b, "                COMPREPLY=($(compgen -W \"%s\" -- \"${cur}\"))\n", strings.Join(c.flags, " "))
+		b.WriteString("            else\n")
+		fmt.Fprintf(&b, "                COMPREPLY=($(compgen -W \"%s\" -- \"${cur}\"))\n", strings.Join(c.subs, " "))
+		switch c.complete {
+		case "file":
+			b.WriteString("                compopt -o filenames 2>/dev/null\n")
+			b.WriteString("                COMPREPLY+=($(compgen -f -- \"${cur}\"))\n")
+		case "dir":
+			b.WriteString("                compopt -o filenames 2>/dev/null\n")
+			b.WriteString("                COMPREPLY+=($(compgen -d -- \"${cur}\"))\n")
+		}
+		b.WriteString("            fi\n")
+		b.WriteString("            ;;\n")
+	}
+	b.WriteString("    esac\n")
+	b.WriteString("}\n")
+	fmt.Fprintf(&b, "complete -F %s %s\n", fn, name)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// completionTree collects the flags, subcommands and positional completion for
+// the command struct t and, recursively, its subcommands.
+func completionTree(t reflect.Type, path string, root bool) completionCommand {
+	c := completionCommand{path: path}
+	addFlags := func(name string, tags map[string]string, typ reflect.Type) {
+		if tags["hidden"] == "true" {
+			return
+		}
+		if tags["short"] != "" {
+			c.flags = append(c.flags, "-"+tags["short"])
+		}
+		if on, off, ok := common.PairFlags(tags, name); ok {
+			c.flags = append(c.flags, on, off)
+		} else if tags["long"] != "" {
+			c.flags = append(c.flags, "--"+tags["long"])
+		}
+		// Booleans that default to true offer their --no- form, as in help
+		if neg := common.NegatedFlag(tags); neg != "" && common.Indirect(typ).Kind() == reflect.Bool && tags["default"] == "true" {
+			c.flags = append(c.flags, neg)
+		}
+	}
+	addPositional := func(tags map[string]string) {
+		if c.complete == "" && !common.IsFlag(tags) {
+			c.complete = tags["complete"]
+		}
+	}
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		switch field.Type.Name() {
+		case "Help":
+			mode := field.Tag.Get("help")
+			if mode == "" {
+				mode = field.Tag.Get("type")
+			}
+			if mode != "subcmd" {
+				c.flags = append(c.flags, "-h", "--help")
+			}
+			if root && (mode == "subcmd" || mode == "both") {
+				c.subs = append(c.subs, "help")
+			}
+			continue
+		case "Version":
+			c.flags = append(c.flags, "--version")
+			continue
+		case "Clifford":
+			if field.Tag.Get("version") != "" {
+				c.flags = append(c.flags, "--version")
+			}
+			continue
+		}
+
+		if field.Type.Kind() != reflect.Struct {
+			if field.Anonymous {
+				continue
+			}
+			tags := common.InlineTags(field)
+			addFlags(field.Name, tags, field.Type)
+			addPositional(tags)
+			continue
+		}
+
+		tags := common.GetTagsFromEmbedded(field.Type, field.Name)
+		if tags["subcmd"] == "true" {
+			if tags["hidden"] == "true" {
+				continue
+			}
+			name := common.SubcommandName(tags, field.Name)
+			child := completionTree(field.Type, path+"/"+name, false)
+			child.aliases = common.SubcommandAliases(tags)
+			c.subs = append(append(c.subs, name), child.aliases...)
+			c.children = append(c.children, child)
+			continue
+		}
+		value, ok := field.Type.FieldByName("Value")
+		if !ok {
+			continue
+		}
+		addFlags(field.Name, tags, value.Type)
+		addPositional(tags)
+		for j := 0; j < field.Type.NumField(); j++ {
+			inner := field.Type.Field(j)
+			if inner.Anonymous || inner.Name == "Value" || inner.Type.Kind() == reflect.Struct {
+				continue
+			}
+			addFlags(inner.Name, common.InlineTags(inner), inner.Type)
+		}
+	}
+	return c
+}
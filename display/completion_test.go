@@ -0,0 +1,109 @@
+package display_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/chriso345/gore/assert"
+
+	"github.com/chriso345/clifford"
+)
+
+func TestGenBashCompletion_PositionalFiles(t *testing.T) {
+	target := struct {
+		clifford.Clifford `name:"app"`
+		clifford.Help
+
+		Run struct {
+			clifford.Subcommand
+			File struct {
+				Value             string
+				clifford.Clifford `complete:"file"`
+			}
+			Verbose struct {
+				Value             bool
+				clifford.Clifford `short:"v" long:"verbose"`
+			}
+		}
+		Status struct {
+			clifford.Subcommand
+		}
+	}{}
+
+	var buf bytes.Buffer
+	err := clifford.GenBashCompletion(&target, &buf)
+	assert.Nil(t, err)
+	script := buf.String()
+
+	assert.True(t, strings.Contains(script, "complete -F _app_completion app"))
+	assert.True(t, strings.Contains(script, `compgen -W "run status"`))
+
+	// File completion is only offered once the `run` subcommand has been typed
+	runCase := script[strings.Index(script, "        /run)"):]
+	runCase = runCase[:strings.Index(runCase, ";;")]
+	assert.True(t, strings.Contains(runCase, `compgen -f -- "${cur}"`))
+	assert.True(t, strings.Contains(runCase, "--verbose"))
+
+	statusCase := script[strings.Index(script, "        /status)"):]
+	statusCase = statusCase[:strings.Index(statusCase, ";;")]
+	assert.False(t, strings.Contains(statusCase, "compgen -f"))
+}
+
+func TestGenBashCompletion_NestedFlags(t *testing.T) {
+	target := struct {
+		clifford.Clifford `name:"app"`
+
+		Color struct {
+			Value             bool `default:"true"`
+			clifford.Clifford `long:"color"`
+		}
+		Remote struct {
+			clifford.Subcommand
+			Add struct {
+				clifford.Subcommand
+				Fetch bool   `short:"f" long:"fetch"`
+				Name  string `long:"name"`
+			}
+		}
+	}{}
+
+	var buf bytes.Buffer
+	assert.Nil(t, clifford.GenBashCompletion(&target, &buf))
+	script := buf.String()
+
+	rootCase := script[strings.Index(script, `        "")`):]
+	rootCase = rootCase[:strings.Index(rootCase, ";;")]
+	assert.True(t, strings.Contains(rootCase, `compgen -W "--color --no-color"`))
+	assert.True(t, strings.Contains(rootCase, `compgen -W "remote"`))
+
+	// Flags of a nested subcommand are only offered once its path is typed
+	addCase := script[strings.Index(script, "        /remote/add)"):]
+	addCase = addCase[:strings.Index(addCase, ";;")]
+	assert.True(t, strings.Contains(addCase, `compgen -W "-f --fetch --name"`))
+	assert.False(t, strings.Contains(rootCase, "--fetch"))
+}
+
+func TestGenBashCompletion_Aliases(t *testing.T) {
+	target := struct {
+		clifford.Clifford `name:"git"`
+
+		Remote struct {
+			clifford.Subcommand `aliases:"r"`
+			Add                 struct {
+				clifford.Subcommand `aliases:"a"`
+				Fetch               bool `long:"fetch"`
+			}
+		}
+	}{}
+
+	var buf bytes.Buffer
+	assert.Nil(t, clifford.GenBashCompletion(&target, &buf))
+	script := buf.String()
+
+	// Aliases are offered next to the names and lead to the same flags
+	assert.True(t, strings.Contains(script, `compgen -W "remote r"`))
+	assert.True(t, strings.Contains(script, `compgen -W "add a"`))
+	assert.True(t, strings.Contains(script, `/r) path="/remote" ;;`))
+	assert.True(t, strings.Contains(script, `/remote/a) path="/remote/add" ;;`))
+}
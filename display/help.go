@@ -2,6 +2,7 @@ package display
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -9,12 +10,24 @@ import (
 
 	"github.com/chriso345/clifford/errors"
 	"github.com/chriso345/clifford/internal/common"
+	"github.com/chriso345/clifford/internal/options"
 )
 
 const maxPad = 16 // maximum padding width to avoid excessive indentation
 
-func BuildHelp(target any, long bool) (string, error) {
-	_ = long // Unused parameter, kept for compatibility
+// WriteHelp renders the help for target like BuildHelp and writes it to w,
+// followed by a newline. ANSI styling is only kept when w is a terminal,
+// unless WithColor says otherwise.
+func WriteHelp(w io.Writer, target any, long bool, opts ...options.Option) error {
+	help, err := BuildHelp(target, long, opts...)
+	if err != nil {
+		return err
+	}
+	return WriteText(w, help, opts...)
+}
+
+func BuildHelp(target any, long bool, opts ...options.Option) (string, error) {
+	cfg := options.New(opts...)
 	if !common.IsStructPtr(target) {
 		return "", errors.NewParseError("invalid type: must pass pointer to struct")
 	}
@@ -36,50 +49,124 @@ func BuildHelp(target any, long bool) (string, error) {
 	}
 
 	var builder strings.Builder
-	builder.WriteString(ansiHelp("Usage:", ansiBold, ansiUnderline) + " ")
-	builder.WriteString(ansiHelp(name, ansiBold))
+	builder.WriteString(ansiHelp(cfg, cfg.Translate("help.usage", "Usage")+":", ansiBold, ansiUnderline) + " ")
 
 	// Collect required args
 	requiredArgs := getRequiredArgs(target)
-	for _, arg := range requiredArgs {
-		// Required positional arguments are shown as angle-bracketed names.
-		builder.WriteString(fmt.Sprintf(" <%s>", strings.ToUpper(arg)))
-	}
-
-	if hasOptions(target) {
-		builder.WriteString(" [OPTIONS]")
+	if usage := commandTag(target, "usage"); usage != "" {
+		// A `usage` tag replaces the generated synopsis verbatim
+		builder.WriteString(usage)
+	} else {
+		builder.WriteString(ansiHelp(cfg, name, ansiBold))
+		for _, arg := range requiredArgs {
+			// Required positional arguments are shown as angle-bracketed names.
+			builder.WriteString(fmt.Sprintf(" <%s>", strings.ToUpper(arg)))
+		}
+		if hasOptions(target) {
+			builder.WriteString(" [OPTIONS]")
+		}
 	}
 	builder.WriteString("\n")
 
 	// Description (if provided) should appear beneath Usage and above the rest of the help.
 	// Only include a top-level description when it is provided on the Clifford embedding.
 	if d := topLevelDescription(target); d != "" {
-		builder.WriteString("\n" + d + "\n")
+		builder.WriteString("\n" + cfg.Translate("desc", d) + "\n")
+	}
+	level := cfg.Verbosity(long)
+	if level >= options.HelpFull {
+		if about := commandTag(target, "long_about"); about != "" {
+			builder.WriteString("\n" + strings.Join(wrapText(cfg.Translate("long_about", about), wrapWidth(cfg)), "\n") + "\n")
+		}
 	}
 
-	// List subcommands if any
-	if subcommandsHelp := buildSubcommandsHelp(target); subcommandsHelp != "" {
-		builder.WriteString("\n" + ansiHelp("Subcommands:", ansiBold, ansiUnderline) + "\n")
-		builder.WriteString(subcommandsHelp)
+	// List subcommands if any, with grouped subcommands under their own headings
+	sections := buildSubcommandsHelp(target, cfg)
+	for _, section := range sections {
+		builder.WriteString("\n" + ansiHelp(cfg, section.title+":", ansiBold, ansiUnderline) + "\n")
+		builder.WriteString(section.body)
+	}
+	// Point at per-command help once, below every subcommand section
+	if hint := subcommandHint(target, cfg); len(sections) > 0 && hint != "" {
+		builder.WriteString("\n" + strings.ReplaceAll(hint, "{name}", name) + "\n")
 	}
 
 	if len(requiredArgs) > 0 {
-		builder.WriteString("\n" + ansiHelp("Arguments:", ansiBold, ansiUnderline) + "\n")
-		builder.WriteString(argsHelp(target))
+		builder.WriteString("\n" + ansiHelp(cfg, cfg.Translate("help.arguments", "Arguments")+":", ansiBold, ansiUnderline) + "\n")
+		builder.WriteString(argsHelp(target, cfg))
 	}
 
 	if hasOptions(target) {
-		builder.WriteString("\n" + ansiHelp("Options:", ansiBold, ansiUnderline) + "\n")
-		builder.WriteString(optionsHelp(target))
+		builder.WriteString("\n" + ansiHelp(cfg, cfg.Translate("help.options", "Options")+":", ansiBold, ansiUnderline) + "\n")
+		builder.WriteString(optionsHelp(target, cfg, level))
+	}
+
+	if level >= options.HelpFull {
+		builder.WriteString(examplesHelp(target, cfg))
 	}
 
 	return builder.String(), nil
 }
 
-// buildSubcommandsHelp returns formatted subcommands lines for the target struct.
-func buildSubcommandsHelp(target any) string {
+// rootTag returns the value of the named tag on the Clifford embedding of t.
+func rootTag(t reflect.Type, key string) string {
+	for i := range t.NumField() {
+		if field := t.Field(i); field.Type.Name() == "Clifford" {
+			return field.Tag.Get(key)
+		}
+	}
+	return ""
+}
+
+// subcommandHint returns the hint shown below the subcommands of target. The
+// built-in hint suggests `app help <command>` when the root exposes help as a
+// subcommand, and `app <command> --help` otherwise.
+func subcommandHint(target any, cfg *options.Config) string {
+	if cfg.SubcommandHint != nil {
+		if *cfg.SubcommandHint == "" {
+			return ""
+		}
+		return cfg.Translate("help.subcommand_hint", *cfg.SubcommandHint)
+	}
+	hint := "Run '{name} <command> --help' for more information on a command."
+	if mode := rootHelpMode(common.GetStructType(target)); mode == "subcmd" || mode == "both" {
+		hint = "Run '{name} help <command>' for more information on a command."
+	}
+	return cfg.Translate("help.subcommand_hint", hint)
+}
+
+// rootHelpMode returns how the Help embedding of t exposes help: "flag",
+// "subcmd" or "both". It is empty when t does not embed Help.
+func rootHelpMode(t reflect.Type) string {
+	for i := range t.NumField() {
+		if f := t.Field(i); f.Type.Name() == "Help" {
+			if mode := f.Tag.Get("help"); mode != "" {
+				return mode
+			}
+			if mode := f.Tag.Get("type"); mode != "" {
+				return mode
+			}
+			return "flag"
+		}
+	}
+	return ""
+}
+
+// subcommandSection is a titled block of subcommand entries in the help output.
+type subcommandSection struct {
+	title string
+	body  string
+}
+
+// buildSubcommandsHelp returns the formatted subcommand sections for the target struct.
+// Ungrouped subcommands are listed under "Subcommands", followed by one section per
+// `group` tag in order of first appearance. Columns are aligned across all sections.
+func buildSubcommandsHelp(target any, cfg *options.Config) []subcommandSection {
 	t := common.GetStructType(target)
-	var entries []struct{ name, desc string }
+	type entry struct{ name, desc string }
+	var ungrouped []entry
+	var groupOrder []string
+	grouped := map[string][]entry{}
 	maxName := 0
 	const maxPad = 16 // maximum padding width to avoid excessive indentation
 
@@ -90,22 +177,33 @@ func buildSubcommandsHelp(target any) string {
 		}
 		// detect subcommand via embedded marker
 		tags := common.GetTagsFromEmbedded(field.Type, field.Name)
-		if tags["subcmd"] != "true" {
+		if tags["subcmd"] != "true" || tags["hidden"] == "true" {
 			continue
 		}
 		name := tags["name"]
 		if name == "" {
 			name = strings.ToLower(field.Name)
 		}
-		desc := tags["desc"]
+		desc := cfg.Translate("desc."+field.Name, tags["desc"])
 		// If the subcommand has an embedded Help with tag "subcmd" or "both",
 		// mention that help is available as a subcommand under this entry.
 		if tagsHelp := tags["help"]; tagsHelp == "subcmd" || tagsHelp == "both" {
 			desc = strings.TrimSpace(desc + " (use '" + name + " help' for more details)")
 		}
-		entries = append(entries, struct{ name, desc string }{name, desc})
-		if len(name) > maxName {
-			maxName = len(name)
+		// Aliases follow the canonical name, as in "checkout (co)"
+		if aliases := common.SubcommandAliases(tags); len(aliases) > 0 {
+			name += " (" + strings.Join(aliases, ", ") + ")"
+		}
+		if group := tags["group"]; group != "" {
+			if _, seen := grouped[group]; !seen {
+				groupOrder = append(groupOrder, group)
+			}
+			grouped[group] = append(grouped[group], entry{name, desc})
+		} else {
+			ungrouped = append(ungrouped, entry{name, desc})
+		}
+		if visibleLen(name) > maxName {
+			maxName = visibleLen(name)
 		}
 	}
 	// Also include a top-level help subcommand if the root exposes help via subcmd/both
@@ -118,7 +216,7 @@ func buildSubcommandsHelp(target any) string {
 				helpTag = f.Tag.Get("type")
 			}
 			if helpTag == "subcmd" || helpTag == "both" {
-				entries = append(entries, struct{ name, desc string }{"help", "Show help for a specific command"})
+				ungrouped = append(ungrouped, entry{"help", cfg.Translate("desc.help_subcommand", "Show help for a specific command")})
 				if len("help") > maxName {
 					maxName = len("help")
 				}
@@ -127,18 +225,29 @@ func buildSubcommandsHelp(target any) string {
 		}
 	}
 
-	var builder strings.Builder
 	pad := min(maxName, maxPad)
-	for _, e := range entries {
-		builder.WriteString(fmt.Sprintf("  %-*s %s\n", pad, e.name, e.desc))
+	format := func(entries []entry) string {
+		var builder strings.Builder
+		for _, e := range entries {
+			builder.WriteString("  " + padVisible(e.name, pad) + " " + e.desc + "\n")
+		}
+		return builder.String()
 	}
-	return builder.String()
+
+	var sections []subcommandSection
+	if len(ungrouped) > 0 {
+		sections = append(sections, subcommandSection{cfg.Translate("help.subcommands", "Subcommands"), format(ungrouped)})
+	}
+	for _, group := range groupOrder {
+		sections = append(sections, subcommandSection{cfg.Translate("help.group."+group, group), format(grouped[group])})
+	}
+	return sections
 }
 
 // === HELPERS ===
 
 // argsHelp generates help text for positional arguments in the target struct.
-func argsHelp(target any) string {
+func argsHelp(target any, cfg *options.Config) string {
 	t := common.GetStructType(target)
 
 	var lines []string
@@ -160,26 +269,26 @@ func argsHelp(target any) string {
 		}
 
 		tags := common.GetTagsFromEmbedded(field.Type, field.Name)
-		if tags["short"] != "" || tags["long"] != "" {
+		if common.IsFlag(tags) || tags["hidden"] == "true" {
 			continue
 		}
 
 		argName := field.Name
-		desc := tags["desc"]
+		desc := cfg.Translate("desc."+field.Name, tags["desc"])
 
 		// Show required positional arguments without square brackets
 		if _, req := tags["required"]; req {
 			line := fmt.Sprintf("  %s", strings.ToUpper(argName))
-			if len(line) > maxLen {
-				maxLen = len(line)
+			if visibleLen(line) > maxLen {
+				maxLen = visibleLen(line)
 			}
 			lines = append(lines, fmt.Sprintf("%s||%s", line, desc))
 			continue
 		}
 
 		line := fmt.Sprintf("  [%s]", strings.ToUpper(argName))
-		if len(line) > maxLen {
-			maxLen = len(line)
+		if visibleLen(line) > maxLen {
+			maxLen = visibleLen(line)
 		}
 		lines = append(lines, fmt.Sprintf("%s||%s", line, desc))
 	}
@@ -189,8 +298,7 @@ func argsHelp(target any) string {
 	pad := min(maxLen, maxPad)
 	for _, line := range lines {
 		parts := strings.SplitN(line, "||", 2)
-		padding := strings.Repeat(" ", pad-len(parts[0])+1)
-		builder.WriteString(fmt.Sprintf("%s%s %s\n", parts[0], padding, parts[1]))
+		builder.WriteString(fmt.Sprintf("%s  %s\n", padVisible(parts[0], pad), parts[1]))
 	}
 	return builder.String()
 }
@@ -217,39 +325,76 @@ func topLevelDescription(target any) string {
 	return desc
 }
 
-// optionsHelp generates help text for options in the target struct.
-func optionsHelp(target any) string {
+// commandTag returns the value of the named tag on the Clifford, Subcommand
+// or Desc embedding of target, such as its `long_about` or `examples`.
+func commandTag(target any, key string) string {
+	t := common.GetStructType(target)
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.Anonymous {
+			continue
+		}
+		switch field.Type.Name() {
+		case "Clifford", "Subcommand", "Desc":
+			if val := field.Tag.Get(key); val != "" {
+				return val
+			}
+		}
+	}
+	return ""
+}
+
+// examplesHelp renders the Examples section listing the semicolon-separated
+// `examples` of target, or returns an empty string when it has none.
+func examplesHelp(target any, cfg *options.Config) string {
+	examples := commandTag(target, "examples")
+	if examples == "" {
+		return ""
+	}
+	var builder strings.Builder
+	builder.WriteString("\n" + ansiHelp(cfg, cfg.Translate("help.examples", "Examples")+":", ansiBold, ansiUnderline) + "\n")
+	for _, example := range strings.Split(examples, ";") {
+		if example = strings.TrimSpace(example); example != "" {
+			builder.WriteString("  " + example + "\n")
+		}
+	}
+	return builder.String()
+}
+
+// optionsHelp generates help text for options in the target struct. Terse help
+// lists only the flag names.
+func optionsHelp(target any, cfg *options.Config, level options.HelpVerbosity) string {
 	t := common.GetStructType(target)
 
 	var lines []string
+	var metaLines []string     // automatic help/version flags, listed after user flags
+	var requiredLines []string // required flags, listed first under WithRequiredFirst
 	maxLen := 0
+	full := level >= options.HelpFull
 
 	for i := range t.NumField() {
 		field := t.Field(i)
 		if field.Type.Name() == "Clifford" {
 			// By default show short + long for version/help; allow disabling via `help_short` or `version_short` tags on the Clifford field.
-			showVersionShort := true
+			showVersionShort := versionShort(t)
 			showHelpShort := true
-			if val := field.Tag.Get("version_short"); val == "false" {
-				showVersionShort = false
-			}
 			if val := field.Tag.Get("help_short"); val == "false" {
 				showHelpShort = false
 			}
 			if field.Tag.Get("version") != "" {
 				if showVersionShort {
-					curr := "  -v, --version||Show version information"
-					lines = append(lines, curr)
+					curr := "  -v, --version||" + cfg.Translate("desc.version", "Show version information")
+					metaLines = append(metaLines, curr)
 					left := strings.SplitN(curr, "||", 2)[0]
-					if len(left) > maxLen {
-						maxLen = len(left)
+					if visibleLen(left) > maxLen {
+						maxLen = visibleLen(left)
 					}
 				} else {
-					curr := "  --version||Show version information"
-					lines = append(lines, curr)
+					curr := "  --version||" + cfg.Translate("desc.version", "Show version information")
+					metaLines = append(metaLines, curr)
 					left := strings.SplitN(curr, "||", 2)[0]
-					if len(left) > maxLen {
-						maxLen = len(left)
+					if visibleLen(left) > maxLen {
+						maxLen = visibleLen(left)
 					}
 				}
 			}
@@ -257,7 +402,7 @@ func optionsHelp(target any) string {
 			helpShown := false
 			// determine if help flag already present
 			helpAdded := false
-			for _, l := range lines {
+			for _, l := range metaLines {
 				if strings.Contains(l, "--help") {
 					helpAdded = true
 					break
@@ -287,18 +432,18 @@ func optionsHelp(target any) string {
 			}
 			if helpShown && !helpAdded {
 				if showHelpShort {
-					curr := "  -h, --help||Show this help message"
-					lines = append(lines, curr)
+					curr := "  -h, --help||" + cfg.Translate("desc.help", "Show this help message")
+					metaLines = append(metaLines, curr)
 					left := strings.SplitN(curr, "||", 2)[0]
-					if len(left) > maxLen {
-						maxLen = len(left)
+					if visibleLen(left) > maxLen {
+						maxLen = visibleLen(left)
 					}
 				} else {
-					curr := "  --help||Show this help message"
-					lines = append(lines, curr)
+					curr := "  --help||" + cfg.Translate("desc.help", "Show this help message")
+					metaLines = append(metaLines, curr)
 					left := strings.SplitN(curr, "||", 2)[0]
-					if len(left) > maxLen {
-						maxLen = len(left)
+					if visibleLen(left) > maxLen {
+						maxLen = visibleLen(left)
 					}
 				}
 			}
@@ -306,11 +451,14 @@ func optionsHelp(target any) string {
 		}
 
 		if field.Type.Name() == "Version" {
-			curr := "  -v, --version||Show version information"
-			lines = append(lines, curr)
+			curr := "  -v, --version||" + cfg.Translate("desc.version", "Show version information")
+			if !versionShort(t) {
+				curr = "  --version||" + cfg.Translate("desc.version", "Show version information")
+			}
+			metaLines = append(metaLines, curr)
 			left := strings.SplitN(curr, "||", 2)[0]
-			if len(left) > maxLen {
-				maxLen = len(left)
+			if visibleLen(left) > maxLen {
+				maxLen = visibleLen(left)
 			}
 			continue
 		}
@@ -320,19 +468,21 @@ func optionsHelp(target any) string {
 		}
 
 		tags := common.GetTagsFromEmbedded(field.Type, field.Name)
-		if tags["short"] == "" && tags["long"] == "" {
+		if !common.IsFlag(tags) || tags["hidden"] == "true" {
 			continue
 		}
 
 		short := tags["short"]
 		long := tags["long"]
-		desc := tags["desc"]
+		desc := cfg.Translate("desc."+field.Name, tags["desc"])
 
 		// Determine the underlying type of the Value field so we can omit type hints for booleans.
 		valField, ok := field.Type.FieldByName("Value")
-		isBool := ok && valField.Type.Kind() == reflect.Bool
+		isBool := ok && common.Indirect(valField.Type).Kind() == reflect.Bool
+		// Count flags take no value either
+		isCount := tags["count"] == "true"
 		var typeHint string
-		if !isBool {
+		if !isBool && !isCount {
 			typeHint = fmt.Sprintf("[%s]", strings.ToUpper(field.Name))
 		}
 
@@ -357,32 +507,217 @@ func optionsHelp(target any) string {
 				flag = fmt.Sprintf("  --%s", long)
 			}
 		}
+		// A paired boolean lists its enable/disable forms in place of its long flag
+		if on, off, ok := common.PairFlags(tags, field.Name); ok && isBool {
+			names := []string{on, off}
+			if short != "" {
+				names = append([]string{"-" + short}, names...)
+			}
+			flag = "  " + strings.Join(names, ", ")
+		}
+		// A boolean that defaults to true is switched off with its --no- form
+		if neg := common.NegatedFlag(tags); neg != "" && isBool && tags["default"] == "true" {
+			flag += ", " + neg
+		}
 
-		// Append default value to description if present
+		// Append default value to description if present, masking secrets
 		if d, ok := tags["default"]; ok && d != "" {
+			if tags["secret"] == "true" {
+				d = "****"
+			}
+			label := cfg.Translate("help.default", "default")
 			if desc == "" {
-				desc = fmt.Sprintf("(default: %s)", d)
+				desc = fmt.Sprintf("(%s: %s)", label, d)
 			} else {
-				desc = fmt.Sprintf("%s (default: %s)", desc, d)
+				desc = fmt.Sprintf("%s (%s: %s)", desc, label, d)
+			}
+		}
+
+		// Show a sample value, which need not match the default
+		if ex := tags["example"]; ex != "" {
+			desc = strings.TrimSpace(fmt.Sprintf("%s (%s %s)", desc, cfg.Translate("help.example", "e.g."), ex))
+		}
+
+		// List the allowed values, unless they are shown as a table below
+		table := choiceTable(tags["choices"], field.Name, cfg)
+		if choices := common.ParseChoices(tags["choices"]); len(choices) > 0 && table == "" {
+			values := make([]string, len(choices))
+			for i, c := range choices {
+				values[i] = c.Value
 			}
+			note := fmt.Sprintf("(%s: %s)", cfg.Translate("help.choices", "choices"), strings.Join(values, ", "))
+			desc = strings.TrimSpace(desc + " " + note)
+		}
+
+		// Count flags may be repeated, as in -vvv
+		if isCount {
+			desc = strings.TrimSpace(desc + " " + fmt.Sprintf("(%s)", cfg.Translate("help.repeatable", "repeatable")))
+		}
+
+		// Show the bounds of a numeric range
+		if note := rangeNote(tags, cfg); note != "" {
+			desc = strings.TrimSpace(desc + " " + note)
+		}
+
+		// Cross-reference the flags this one must be used together with
+		if req := tags["requires"]; req != "" {
+			note := fmt.Sprintf("(%s %s)", cfg.Translate("help.requires", "use with"), flagList(t, req))
+			desc = strings.TrimSpace(desc + " " + note)
+		}
+		if conflicts := tags["conflicts"]; conflicts != "" {
+			note := fmt.Sprintf("(%s %s)", cfg.Translate("help.conflicts", "not with"), flagList(t, conflicts))
+			desc = strings.TrimSpace(desc + " " + note)
+		}
+
+		// Name the environment variable the flag falls back to
+		if env := tags["env"]; env != "" && full {
+			desc = strings.TrimSpace(fmt.Sprintf("%s (%s: $%s)", desc, cfg.Translate("help.env", "env"), env))
 		}
 
-		if len(flag) > maxLen {
-			maxLen = len(flag)
+		// Document the version that introduced the flag
+		if since := tags["since"]; since != "" {
+			desc = strings.TrimSpace(fmt.Sprintf("%s (%s %s)", desc, cfg.Translate("help.since", "since"), since))
+		}
+
+		if visibleLen(flag) > maxLen {
+			maxLen = visibleLen(flag)
+		}
+		block := []string{fmt.Sprintf("%s||%s", flag, desc)}
+
+		// Choices with descriptions are listed beneath the flag in full help
+		if full && table != "" {
+			block = append(block, table+"||")
+		}
+		if cfg.RequiredFirst && tags["required"] == "true" {
+			requiredLines = append(requiredLines, block...)
+		} else {
+			lines = append(lines, block...)
 		}
-		lines = append(lines, fmt.Sprintf("%s||%s", flag, desc))
 	}
+	lines = append(append(requiredLines, lines...), metaLines...)
 
-	// Format with aligned colons
+	// Format with aligned colons, wrapping descriptions so continuation lines
+	// line up under the description column.
 	var builder strings.Builder
+	indent := maxLen + 2
 	for _, line := range lines {
 		parts := strings.SplitN(line, "||", 2)
-		padding := strings.Repeat(" ", maxLen-len(parts[0]))
-		builder.WriteString(fmt.Sprintf("%s%s  %s\n", parts[0], padding, parts[1]))
+		if level <= options.HelpTerse || parts[1] == "" {
+			builder.WriteString(parts[0] + "\n")
+			continue
+		}
+		descLines := wrapText(parts[1], wrapWidth(cfg)-indent)
+		builder.WriteString(fmt.Sprintf("%s  %s\n", padVisible(parts[0], maxLen), descLines[0]))
+		for _, cont := range descLines[1:] {
+			builder.WriteString(strings.Repeat(" ", indent) + cont + "\n")
+		}
 	}
 	return builder.String()
 }
 
+// choiceTable renders the choices of a `choices` tag that carry descriptions,
+// such as "debug=verbose logging,info=normal", as an indented two-column
+// table. It is empty when no choice has a description.
+func choiceTable(tag, fieldName string, cfg *options.Config) string {
+	choices := common.ParseChoices(tag)
+	width, described := 0, false
+	for _, c := range choices {
+		width = max(width, visibleLen(c.Value))
+		described = described || c.Desc != ""
+	}
+	if !described {
+		return ""
+	}
+	rows := make([]string, len(choices))
+	for i, c := range choices {
+		desc := cfg.Translate("desc."+fieldName+"."+c.Value, c.Desc)
+		rows[i] = strings.TrimRight("      "+padVisible(c.Value, width)+"  "+desc, " ")
+	}
+	return strings.Join(rows, "\n")
+}
+
+// rangeNote describes the bounds declared by the `min` and `max` tags, such as
+// "(range: 1-65535)", or returns an empty string when there are none.
+func rangeNote(tags map[string]string, cfg *options.Config) string {
+	lo, hi := tags["min"], tags["max"]
+	switch {
+	case lo != "" && hi != "":
+		return fmt.Sprintf("(%s: %s-%s)", cfg.Translate("help.range", "range"), lo, hi)
+	case lo != "":
+		return fmt.Sprintf("(%s: %s)", cfg.Translate("help.min", "min"), lo)
+	case hi != "":
+		return fmt.Sprintf("(%s: %s)", cfg.Translate("help.max", "max"), hi)
+	}
+	return ""
+}
+
+// flagList renders a comma-separated list of flag names, such as "tls-key,k",
+// as command-line flags: "--tls-key, -k". Names of fields of the command
+// struct t, such as "TLSKey", are rendered as their flags.
+func flagList(t reflect.Type, names string) string {
+	var flags []string
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		field, isField := t.FieldByName(name)
+		var tags map[string]string
+		if isField {
+			if field.Type.Kind() == reflect.Struct {
+				tags = common.GetTagsFromEmbedded(field.Type, field.Name)
+			} else {
+				tags = map[string]string{"short": field.Tag.Get("short"), "long": field.Tag.Get("long")}
+			}
+		}
+		switch {
+		case name == "":
+			continue
+		case tags["long"] != "":
+			flags = append(flags, "--"+tags["long"])
+		case tags["short"] != "":
+			flags = append(flags, "-"+tags["short"])
+		case len(name) == 1:
+			flags = append(flags, "-"+name)
+		default:
+			flags = append(flags, "--"+name)
+		}
+	}
+	return strings.Join(flags, ", ")
+}
+
+// versionShort reports whether -v is listed as the short form of --version
+// for the command struct t. As in the parser, it is not when t declares a -v
+// flag of its own or its Clifford embedding is tagged `version_short:"false"`.
+func versionShort(t reflect.Type) bool {
+	if root, ok := t.FieldByName("Clifford"); ok && root.Tag.Get("version_short") == "false" {
+		return false
+	}
+	for i := range t.NumField() {
+		field := t.Field(i)
+		switch {
+		case field.Anonymous:
+			continue
+		case field.Type.Kind() != reflect.Struct:
+			if field.Tag.Get("short") == "v" {
+				return false
+			}
+			continue
+		}
+		tags := common.GetTagsFromEmbedded(field.Type, field.Name)
+		if tags["subcmd"] == "true" {
+			continue
+		}
+		if _, ok := field.Type.FieldByName("Value"); ok && tags["short"] == "v" {
+			return false
+		}
+		for j := range field.Type.NumField() {
+			inner := field.Type.Field(j)
+			if !inner.Anonymous && inner.Name != "Value" && inner.Tag.Get("short") == "v" {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // getRequiredArgs returns a list of required argument names from the target struct.
 func getRequiredArgs(target any) []string {
 	t := common.GetStructType(target)
@@ -395,7 +730,7 @@ func getRequiredArgs(target any) []string {
 		}
 
 		tags := common.GetTagsFromEmbedded(field.Type, field.Name)
-		if tags["short"] != "" || tags["long"] != "" {
+		if common.IsFlag(tags) || tags["hidden"] == "true" {
 			continue
 		}
 
@@ -441,7 +776,7 @@ func hasOptions(target any) bool {
 		}
 
 		tags := common.GetTagsFromEmbedded(field.Type, field.Name)
-		if tags["short"] != "" || tags["long"] != "" {
+		if common.IsFlag(tags) && tags["hidden"] != "true" {
 			return true
 		}
 	}
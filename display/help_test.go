@@ -1,6 +1,9 @@
 package display_test
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -25,7 +28,7 @@ func TestBuildHelp_ValidInput(t *testing.T) {
 		}
 	}{}
 
-	help, err := clifford.BuildHelp(&target, false)
+	help, err := clifford.BuildHelp(&target, true)
 	assert.Nil(t, err)
 	assert.StringContains(t, help, "Usage:")
 	assert.StringContains(t, help, "[INPUT]")
@@ -189,3 +192,804 @@ func filterLinesContaining(lines []string, terms ...string) []string {
 	}
 	return out
 }
+
+func TestBuildHelp_MultiLineDescription(t *testing.T) {
+	target := struct {
+		clifford.Clifford `name:"tool"`
+
+		Mode struct {
+			Value             string
+			clifford.Clifford `long:"mode" desc:"Select a mode:\n  - fast: skip checks\n  - safe: run every check"`
+		}
+	}{}
+
+	help, err := clifford.BuildHelp(&target, true)
+	assert.Nil(t, err)
+
+	lines := strings.Split(help, "\n")
+	first := filterLinesContaining(lines, "--mode")
+	assert.Equal(t, len(first), 1)
+	col := strings.Index(first[0], "Select")
+
+	bullets := filterLinesContaining(lines, "- fast", "- safe")
+	assert.Equal(t, len(bullets), 2)
+	for _, line := range bullets {
+		// Hard breaks start a new line aligned under the description column
+		assert.Equal(t, strings.Index(line, "-"), col+2)
+	}
+}
+
+func TestBuildHelp_LongURLOverflows(t *testing.T) {
+	url := "https://example.com/" + strings.Repeat("very-long-path-segment/", 5)
+	target := struct {
+		clifford.Clifford `name:"tool"`
+
+		Docs struct {
+			Value             string
+			clifford.Clifford `long:"docs" desc:"Documentation lives at https://example.com/very-long-path-segment/very-long-path-segment/very-long-path-segment/very-long-path-segment/very-long-path-segment/ for reference"`
+		}
+	}{}
+
+	help, err := clifford.BuildHelp(&target, true)
+	assert.Nil(t, err)
+
+	// The URL is never split, even though it is wider than the terminal
+	assert.True(t, strings.Contains(help, url))
+	for _, line := range strings.Split(help, "\n") {
+		if !strings.Contains(line, url) {
+			assert.True(t, len(line) <= 80)
+		}
+	}
+}
+
+func TestBuildHelp_ColumnsWidth(t *testing.T) {
+	target := struct {
+		clifford.Clifford `name:"tool"`
+
+		Mode struct {
+			Value             string
+			clifford.Clifford `long:"mode" desc:"Choose how thoroughly the tool checks its input before writing any output files"`
+		}
+	}{}
+
+	longest := func(help string) int {
+		width := 0
+		for _, line := range strings.Split(help, "\n") {
+			width = max(width, len(line))
+		}
+		return width
+	}
+
+	t.Setenv("COLUMNS", "50")
+	help, err := clifford.BuildHelp(&target, true)
+	assert.Nil(t, err)
+	assert.True(t, longest(help) <= 50)
+	assert.True(t, longest(help) > 40)
+
+	// An explicit width takes precedence over COLUMNS
+	help, err = clifford.BuildHelp(&target, true, clifford.WithWidth(100))
+	assert.Nil(t, err)
+	assert.True(t, longest(help) > 50)
+}
+
+func TestBuildHelp_GroupedSubcommands(t *testing.T) {
+	target := struct {
+		clifford.Clifford `name:"kubectl"`
+
+		Create struct {
+			clifford.Subcommand `group:"Basic Commands"`
+			clifford.Desc       `desc:"Create a resource"`
+		}
+		Cordon struct {
+			clifford.Subcommand `group:"Cluster Management"`
+			clifford.Desc       `desc:"Mark node as unschedulable"`
+		}
+		Delete struct {
+			clifford.Subcommand `group:"Basic Commands"`
+			clifford.Desc       `desc:"Delete resources"`
+		}
+	}{}
+
+	help, err := clifford.BuildHelp(&target, false)
+	assert.Nil(t, err)
+
+	basic := strings.Index(help, "Basic Commands:")
+	cluster := strings.Index(help, "Cluster Management:")
+	assert.True(t, basic >= 0)
+	assert.True(t, cluster > basic)
+	assert.False(t, strings.Contains(help, "Subcommands:"))
+
+	// Each subcommand is listed under its own group heading
+	assert.True(t, strings.Index(help, "create") > basic)
+	assert.True(t, strings.Index(help, "delete") < cluster)
+	assert.True(t, strings.Index(help, "cordon") > cluster)
+}
+
+func TestBuildHelp_MissingCliffordEmbedding(t *testing.T) {
+	target := struct {
+		Foo struct {
+			Value             string
+			clifford.Clifford `long:"foo" desc:"A foo flag"`
+		}
+	}{}
+
+	// Help still renders, using the program name in place of a declared name
+	help, err := clifford.BuildHelp(&target, false)
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(help, filepath.Base(os.Args[0])))
+	assert.True(t, strings.Contains(help, "--foo"))
+}
+
+func TestBuildHelp_SecretDefaultMasked(t *testing.T) {
+	target := struct {
+		clifford.Clifford `name:"tool"`
+
+		Password struct {
+			Value             string `default:"hunter2"`
+			clifford.Clifford `long:"password" desc:"Database password" secret:"true"`
+		}
+	}{}
+
+	help, err := clifford.BuildHelp(&target, true)
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(help, "(default: ****)"))
+	assert.False(t, strings.Contains(help, "hunter2"))
+}
+
+func TestBuildHelp_DescriptionPrecedence(t *testing.T) {
+	target := struct {
+		clifford.Clifford `name:"tool"`
+
+		Before struct {
+			Value             string
+			clifford.Desc     `desc:"from Desc"`
+			clifford.Clifford `long:"before" desc:"from Clifford"`
+		}
+		After struct {
+			Value             string
+			clifford.Clifford `long:"after" desc:"from Clifford"`
+			clifford.Desc     `desc:"from Desc"`
+		}
+		Only struct {
+			Value             string
+			clifford.Clifford `long:"only"`
+			clifford.Desc     `desc:"from Desc"`
+		}
+	}{}
+
+	help, err := clifford.BuildHelp(&target, true)
+	assert.Nil(t, err)
+
+	// An explicit desc on Clifford wins regardless of field order
+	lines := strings.Split(help, "\n")
+	for _, line := range filterLinesContaining(lines, "--before", "--after") {
+		assert.True(t, strings.Contains(line, "from Clifford"))
+	}
+	only := filterLinesContaining(lines, "--only")
+	assert.Equal(t, len(only), 1)
+	assert.True(t, strings.Contains(only[0], "from Desc"))
+}
+
+func TestBuildHelp_Translator(t *testing.T) {
+	target := struct {
+		clifford.Clifford `name:"tool" desc:"A translated tool"`
+
+		Port struct {
+			Value             int
+			clifford.Clifford `long:"port" desc:"Port to listen on"`
+		}
+		Serve struct {
+			clifford.Subcommand
+			clifford.Desc `desc:"Start the server"`
+		}
+	}{}
+
+	var keys []string
+	translator := func(key, text string) string {
+		keys = append(keys, key)
+		switch {
+		case strings.HasPrefix(key, "desc"):
+			return strings.ToUpper(text)
+		case key == "help.options":
+			return "Optionen"
+		}
+		return text
+	}
+
+	help, err := clifford.BuildHelp(&target, true, clifford.WithTranslator(translator))
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(help, "A TRANSLATED TOOL"))
+	assert.True(t, strings.Contains(help, "PORT TO LISTEN ON"))
+	assert.True(t, strings.Contains(help, "START THE SERVER"))
+	assert.True(t, strings.Contains(help, "Optionen:"))
+	assert.True(t, strings.Contains(strings.Join(keys, " "), "desc.Port"))
+}
+
+func TestBuildHelp_RequiresCrossReference(t *testing.T) {
+	target := struct {
+		clifford.Clifford `name:"tool"`
+
+		TLSCert struct {
+			Value             string
+			clifford.Clifford `long:"tls-cert" desc:"TLS certificate" requires:"tls-key"`
+		}
+		TLSKey struct {
+			Value             string
+			clifford.Clifford `long:"tls-key" desc:"TLS private key" requires:"tls-cert,c"`
+		}
+	}{}
+
+	help, err := clifford.BuildHelp(&target, true)
+	assert.Nil(t, err)
+
+	cert := filterLinesContaining(strings.Split(help, "\n"), "--tls-cert [")
+	assert.Equal(t, len(cert), 1)
+	assert.True(t, strings.Contains(cert[0], "TLS certificate (use with --tls-key)"))
+
+	key := filterLinesContaining(strings.Split(help, "\n"), "--tls-key [")
+	assert.Equal(t, len(key), 1)
+	assert.True(t, strings.Contains(key[0], "(use with --tls-cert, -c)"))
+}
+
+func TestBuildHelp_ConstraintFieldNames(t *testing.T) {
+	target := struct {
+		clifford.Clifford `name:"app"`
+
+		TLSCert struct {
+			Value             string
+			clifford.Clifford `long:"tls-cert" desc:"TLS certificate" requires:"TLSKey"`
+		}
+		TLSKey struct {
+			Value             string
+			clifford.Clifford `short:"k"`
+		}
+		Insecure struct {
+			Value             bool
+			clifford.Clifford `long:"insecure" desc:"Skip TLS" conflicts:"TLSCert"`
+		}
+	}{}
+
+	help, err := clifford.BuildHelp(&target, true)
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(help, "TLS certificate (use with -k)"))
+	assert.True(t, strings.Contains(help, "Skip TLS (not with --tls-cert)"))
+}
+
+func TestBuildHelp_ShortVersusLong(t *testing.T) {
+	target := struct {
+		clifford.Clifford `name:"tool" desc:"A tool" long_about:"Tool does many things in great detail." examples:"tool --port 80; tool --verbose"`
+
+		Port struct {
+			Value             int `default:"8080"`
+			clifford.Clifford `long:"port" desc:"Port to listen on"`
+		}
+		Verbose struct {
+			Value             bool
+			clifford.Clifford `short:"v" long:"verbose" desc:"Enable verbose output"`
+		}
+	}{}
+
+	short, err := clifford.BuildHelp(&target, false)
+	assert.Nil(t, err)
+	long, err := clifford.BuildHelp(&target, true)
+	assert.Nil(t, err)
+
+	// The summary lists flag names only
+	assert.True(t, strings.Contains(short, "--port [PORT]"))
+	assert.True(t, strings.Contains(short, "-v, --verbose"))
+	assert.False(t, strings.Contains(short, "Port to listen on"))
+	assert.False(t, strings.Contains(short, "great detail"))
+	assert.False(t, strings.Contains(short, "Examples:"))
+
+	// The manual adds descriptions, the long description and examples
+	assert.True(t, strings.Contains(long, "Port to listen on (default: 8080)"))
+	assert.True(t, strings.Contains(long, "Enable verbose output"))
+	assert.True(t, strings.Contains(long, "Tool does many things in great detail."))
+	assert.True(t, strings.Contains(long, "Examples:"))
+	assert.True(t, strings.Contains(long, "  tool --port 80\n  tool --verbose\n"))
+	assert.True(t, len(long) > len(short))
+}
+
+func TestBuildHelp_ExampleValue(t *testing.T) {
+	target := struct {
+		clifford.Clifford `name:"tool"`
+
+		Port struct {
+			Value             int `default:"8080"`
+			clifford.Clifford `long:"port" desc:"Port to listen on" example:"9090"`
+		}
+		Host struct {
+			Value             string
+			clifford.Clifford `long:"host" example:"db.internal"`
+		}
+	}{}
+
+	help, err := clifford.BuildHelp(&target, true)
+	assert.Nil(t, err)
+
+	port := filterLinesContaining(strings.Split(help, "\n"), "--port")
+	assert.Equal(t, len(port), 1)
+	assert.True(t, strings.Contains(port[0], "Port to listen on (default: 8080) (e.g. 9090)"))
+
+	host := filterLinesContaining(strings.Split(help, "\n"), "--host")
+	assert.Equal(t, len(host), 1)
+	assert.True(t, strings.Contains(host[0], "(e.g. db.internal)"))
+	assert.False(t, strings.Contains(host[0], "default"))
+}
+
+func TestBuildHelp_PairedBoolFlag(t *testing.T) {
+	target := struct {
+		clifford.Clifford `name:"tool"`
+
+		Color struct {
+			Value             bool
+			clifford.Clifford `short:"c" long:"color" pair:"enable,disable" desc:"Colorize output"`
+		}
+	}{}
+
+	help, err := clifford.BuildHelp(&target, true)
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(help, "-c, --enable-color, --disable-color  Colorize output"))
+	assert.False(t, strings.Contains(help, " --color"))
+}
+
+func TestWriteHelp_Buffer(t *testing.T) {
+	target := struct {
+		clifford.Clifford `name:"app"`
+
+		Verbose struct {
+			Value             bool
+			clifford.Clifford `short:"v" long:"verbose" desc:"Enable verbose output"`
+		}
+	}{}
+
+	var buf bytes.Buffer
+	err := clifford.WriteHelp(&buf, &target, true)
+	assert.Nil(t, err)
+
+	out := buf.String()
+	assert.StringContains(t, out, "Usage: app [OPTIONS]")
+	assert.StringContains(t, out, "--verbose")
+	assert.StringContains(t, out, "Enable verbose output")
+	// A buffer is not a terminal, so no ANSI styling is written
+	assert.False(t, strings.Contains(out, "\033["))
+
+	err = clifford.WriteHelp(&buf, 42, true)
+	assert.NotNil(t, err)
+}
+
+func TestBuildHelp_AutomaticFlagsLast(t *testing.T) {
+	target := struct {
+		clifford.Clifford `name:"app" version:"1.0.0"`
+		clifford.Help
+
+		Verbose struct {
+			Value             bool
+			clifford.Clifford `short:"V" long:"verbose" desc:"Enable verbose output"`
+		}
+		Output struct {
+			Value             string
+			clifford.Clifford `short:"o" long:"output" desc:"Output file"`
+		}
+	}{}
+
+	help, err := clifford.BuildHelp(&target, true)
+	assert.Nil(t, err)
+
+	options := help[strings.Index(help, "Options:"):]
+	userLast := strings.Index(options, "--output")
+	assert.True(t, strings.Index(options, "--verbose") < userLast)
+	assert.True(t, strings.Index(options, "--version") > userLast)
+	assert.True(t, strings.Index(options, "--help") > userLast)
+}
+
+func TestBuildHelp_SubcommandHint(t *testing.T) {
+	target := struct {
+		clifford.Clifford `name:"app"`
+
+		Serve struct {
+			clifford.Subcommand
+			clifford.Desc `desc:"Start the server"`
+		}
+	}{}
+
+	help, err := clifford.BuildHelp(&target, true)
+	assert.Nil(t, err)
+	hint := "Run 'app <command> --help' for more information on a command."
+	assert.StringContains(t, help, hint)
+	assert.True(t, strings.Index(help, hint) > strings.Index(help, "Start the server"))
+
+	help, err = clifford.BuildHelp(&target, true, clifford.WithSubcommandHint("See '{name} <command> -h'."))
+	assert.Nil(t, err)
+	assert.StringContains(t, help, "See 'app <command> -h'.")
+	assert.False(t, strings.Contains(help, hint))
+
+	help, err = clifford.BuildHelp(&target, true, clifford.WithSubcommandHint(""))
+	assert.Nil(t, err)
+	assert.False(t, strings.Contains(help, "more information"))
+
+	// Commands without subcommands show no hint
+	plain := struct {
+		clifford.Clifford `name:"app"`
+	}{}
+	help, err = clifford.BuildHelp(&plain, true)
+	assert.Nil(t, err)
+	assert.False(t, strings.Contains(help, "more information"))
+}
+
+func TestBuildHelp_ChoiceDescriptions(t *testing.T) {
+	target := struct {
+		clifford.Clifford `name:"app"`
+
+		Level struct {
+			Value             string
+			clifford.Clifford `long:"level" desc:"Log level" choices:"debug=verbose logging,info=normal output,quiet"`
+		}
+	}{}
+
+	help, err := clifford.BuildHelp(&target, true)
+	assert.Nil(t, err)
+	assert.StringContains(t, help, "Log level\n")
+	assert.StringContains(t, help, "      debug  verbose logging\n")
+	assert.StringContains(t, help, "      info   normal output\n")
+	assert.StringContains(t, help, "      quiet\n")
+	assert.True(t, strings.Index(help, "--level") < strings.Index(help, "debug"))
+
+	// The one-screen summary leaves the table out
+	short, err := clifford.BuildHelp(&target, false)
+	assert.Nil(t, err)
+	assert.False(t, strings.Contains(short, "verbose logging"))
+}
+
+func TestBuildHelp_NoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	help, err := clifford.BuildHelp(&struct {
+		clifford.Clifford `name:"tool"`
+		clifford.Help
+	}{}, true)
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(help, "Usage: tool"))
+	assert.False(t, strings.Contains(help, "\033["))
+}
+
+func TestBuildHelp_ChoicesNote(t *testing.T) {
+	target := struct {
+		clifford.Clifford `name:"app"`
+
+		LogLevel struct {
+			Value             string
+			clifford.Clifford `long:"log-level" desc:"Log level" choices:"debug,info,warn,error"`
+		}
+	}{}
+
+	help, err := clifford.BuildHelp(&target, true)
+	assert.Nil(t, err)
+	assert.StringContains(t, help, "Log level (choices: debug, info, warn, error)")
+}
+
+func TestBuildHelp_RequiredFirst(t *testing.T) {
+	target := struct {
+		clifford.Clifford `name:"app"`
+		clifford.Help
+
+		Verbose struct {
+			Value             bool
+			clifford.Clifford `long:"verbose"`
+		}
+		Token struct {
+			Value             string
+			clifford.Clifford `long:"token" required:"true"`
+		}
+		Color struct {
+			Value             bool
+			clifford.Clifford `long:"color"`
+		}
+		Region struct {
+			Value             string
+			clifford.Clifford `long:"region" required:"true"`
+		}
+	}{}
+
+	help, err := clifford.BuildHelp(&target, false, clifford.WithRequiredFirst())
+	assert.Nil(t, err)
+	order := []string{"--token", "--region", "--verbose", "--color", "--help"}
+	for i := 1; i < len(order); i++ {
+		assert.True(t, strings.Index(help, order[i-1]) < strings.Index(help, order[i]))
+	}
+
+	// Without the option, declaration order is kept
+	help, err = clifford.BuildHelp(&target, false)
+	assert.Nil(t, err)
+	assert.True(t, strings.Index(help, "--verbose") < strings.Index(help, "--token"))
+}
+
+func TestBuildHelp_RangeNote(t *testing.T) {
+	target := struct {
+		clifford.Clifford `name:"app"`
+
+		Port struct {
+			Value             int
+			clifford.Clifford `long:"port" desc:"Port to listen on" min:"1" max:"65535"`
+		}
+	}{}
+
+	help, err := clifford.BuildHelp(&target, true)
+	assert.Nil(t, err)
+	assert.StringContains(t, help, "Port to listen on (range: 1-65535)")
+}
+
+func TestBuildHelp_CountFlag(t *testing.T) {
+	target := struct {
+		clifford.Clifford `name:"app"`
+
+		Verbose struct {
+			Value             int
+			clifford.Clifford `short:"v" long:"verbose" desc:"Increase verbosity" count:"true"`
+		}
+	}{}
+
+	help, err := clifford.BuildHelp(&target, true)
+	assert.Nil(t, err)
+	assert.StringContains(t, help, "-v, --verbose  Increase verbosity (repeatable)")
+}
+
+func TestBuildHelp_NegatedFlag(t *testing.T) {
+	target := struct {
+		clifford.Clifford `name:"app"`
+
+		Color struct {
+			Value             bool `default:"true"`
+			clifford.Clifford `long:"color" desc:"Colorize output"`
+		}
+		Verbose struct {
+			Value             bool
+			clifford.Clifford `long:"verbose" desc:"Verbose output"`
+		}
+	}{}
+
+	help, err := clifford.BuildHelp(&target, true)
+	assert.Nil(t, err)
+	assert.StringContains(t, help, "--color, --no-color  Colorize output")
+	// Only booleans that default to true advertise their --no- form
+	assert.False(t, strings.Contains(help, "--no-verbose"))
+}
+
+func TestBuildHelp_SinceNote(t *testing.T) {
+	target := struct {
+		clifford.Clifford `name:"app"`
+
+		Format struct {
+			Value             string
+			clifford.Clifford `long:"format" desc:"Output format" since:"1.4.0"`
+		}
+	}{}
+
+	help, err := clifford.BuildHelp(&target, true)
+	assert.Nil(t, err)
+	assert.StringContains(t, help, "--format [FORMAT]  Output format (since 1.4.0)")
+}
+
+func TestBuildHelp_Verbosity(t *testing.T) {
+	target := struct {
+		clifford.Clifford `name:"app" long_about:"A longer story." examples:"app --port 80"`
+
+		Port struct {
+			Value             int `default:"8080"`
+			clifford.Clifford `long:"port" desc:"Port to listen on" env:"APP_PORT"`
+		}
+	}{}
+
+	build := func(v clifford.HelpVerbosity) string {
+		help, err := clifford.BuildHelp(&target, false, clifford.WithHelpVerbosity(v))
+		assert.Nil(t, err)
+		return help
+	}
+
+	terse := build(clifford.HelpTerse)
+	assert.True(t, strings.Contains(terse, "--port [PORT]"))
+	assert.False(t, strings.Contains(terse, "Port to listen on"))
+
+	normal := build(clifford.HelpNormal)
+	assert.True(t, strings.Contains(normal, "Port to listen on (default: 8080)"))
+	assert.False(t, strings.Contains(normal, "$APP_PORT"))
+	assert.False(t, strings.Contains(normal, "A longer story."))
+	assert.False(t, strings.Contains(normal, "Examples:"))
+
+	full := build(clifford.HelpFull)
+	assert.True(t, strings.Contains(full, "Port to listen on (default: 8080) (env: $APP_PORT)"))
+	assert.True(t, strings.Contains(full, "A longer story."))
+	assert.True(t, strings.Contains(full, "Examples:"))
+
+	// Without the option, long still picks between terse and full
+	help, err := clifford.BuildHelp(&target, true)
+	assert.Nil(t, err)
+	assert.Equal(t, help, full)
+}
+
+func TestBuildHelp_SubcommandAliases(t *testing.T) {
+	target := struct {
+		clifford.Clifford `name:"git"`
+
+		Checkout struct {
+			clifford.Subcommand `aliases:"co,ck"`
+			clifford.Desc       `desc:"Switch branches"`
+		}
+	}{}
+
+	help, err := clifford.BuildHelp(&target, false)
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(help, "checkout (co, ck)"))
+}
+
+func TestBuildHelp_LongAboutEmbeddings(t *testing.T) {
+	target := struct {
+		clifford.Clifford `name:"app"`
+		clifford.Desc     `desc:"Short summary" long_about:"The whole story."`
+
+		Serve struct {
+			clifford.Subcommand `long_about:"Serve files until interrupted."`
+			clifford.Desc       `desc:"Serve files"`
+			Port                int `long:"port"`
+		}
+	}{}
+
+	help, err := clifford.BuildHelp(&target, true)
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(help, "Short summary\n\nThe whole story.\n"))
+	help, err = clifford.BuildHelp(&target, false)
+	assert.Nil(t, err)
+	assert.False(t, strings.Contains(help, "The whole story."))
+
+	// Subcommand help shows the subcommand's own long_about in long form only
+	sub, err := clifford.BuildHelpWithParent(&target, "serve", &target.Serve, true)
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(sub, "Serve files until interrupted."))
+	sub, err = clifford.BuildHelpWithParent(&target, "serve", &target.Serve, false)
+	assert.Nil(t, err)
+	assert.False(t, strings.Contains(sub, "Serve files until interrupted."))
+}
+
+func TestBuildHelpWithParent_Examples(t *testing.T) {
+	parent := struct {
+		clifford.Clifford `name:"app"`
+
+		Serve struct {
+			clifford.Subcommand `examples:"app serve --port 9000; app serve --tls"`
+			Port                int `long:"port"`
+		}
+	}{}
+
+	help, err := clifford.BuildHelpWithParent(&parent, "serve", &parent.Serve, true)
+	assert.Nil(t, err)
+	examples := strings.Index(help, "Examples:")
+	assert.True(t, examples > strings.Index(help, "Options:"))
+	assert.True(t, strings.HasSuffix(help, "\n  app serve --port 9000\n  app serve --tls\n"))
+
+	help, err = clifford.BuildHelpWithParent(&parent, "serve", &parent.Serve, false)
+	assert.Nil(t, err)
+	assert.False(t, strings.Contains(help, "Examples:"))
+}
+
+func TestBuildHelp_UsageOverride(t *testing.T) {
+	target := struct {
+		clifford.Clifford `name:"tool" usage:"tool [OPTIONS] <SRC>... <DST>"`
+
+		Force bool `short:"f"`
+		Sync  struct {
+			clifford.Subcommand `usage:"tool sync [--dry-run] <REMOTE>"`
+			DryRun              bool `long:"dry-run"`
+		}
+	}{}
+
+	t.Setenv("NO_COLOR", "1")
+	help, err := clifford.BuildHelp(&target, false)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(help, "Usage: tool [OPTIONS] <SRC>... <DST>\n"))
+
+	sub, err := clifford.BuildHelpWithParent(&target, "sync", &target.Sync, false)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(sub, "Usage: tool sync [--dry-run] <REMOTE>\n"))
+}
+
+func TestBuildHelp_HiddenFields(t *testing.T) {
+	target := struct {
+		clifford.Clifford `name:"tool"`
+
+		Verbose struct {
+			Value             bool
+			clifford.Clifford `short:"v" long:"verbose" desc:"Enable verbose output"`
+		}
+		Trace struct {
+			Value             bool
+			clifford.Clifford `long:"trace-internals" desc:"Dump internal state" hidden:"true"`
+		}
+		Serve struct {
+			clifford.Subcommand
+			clifford.Desc `desc:"Serve files"`
+		}
+		Debug struct {
+			clifford.Subcommand `hidden:"true"`
+			clifford.Desc       `desc:"Inspect internals"`
+		}
+		Source struct {
+			Value string
+			clifford.Required
+		}
+		Profile struct {
+			Value string `hidden:"true"`
+			clifford.Required
+		}
+	}{}
+
+	t.Setenv("NO_COLOR", "1")
+	help, err := clifford.BuildHelp(&target, true)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(help, "Usage: tool <SOURCE> "))
+	assert.False(t, strings.Contains(help, "PROFILE"))
+	assert.True(t, strings.Contains(help, "--verbose"))
+	assert.True(t, strings.Contains(help, "Serve files"))
+	assert.False(t, strings.Contains(help, "--trace-internals"))
+	assert.False(t, strings.Contains(help, "Inspect internals"))
+}
+
+func TestBuildHelp_VersionShortDisabled(t *testing.T) {
+	target := struct {
+		clifford.Clifford `name:"tool" version_short:"false"`
+		clifford.Version  `version:"1.2.3"`
+	}{}
+
+	t.Setenv("NO_COLOR", "1")
+	help, err := clifford.BuildHelp(&target, false)
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(help, "  --version"))
+	assert.False(t, strings.Contains(help, "-v, --version"))
+}
+
+func TestBuildHelp_ColorDisabled(t *testing.T) {
+	target := struct {
+		clifford.Clifford `name:"tool"`
+
+		Serve struct {
+			clifford.Subcommand
+			Port int `long:"port"`
+		}
+	}{}
+
+	help, err := clifford.BuildHelp(&target, true, clifford.WithColor(false))
+	assert.Nil(t, err)
+	assert.False(t, strings.Contains(help, "\x1b["))
+
+	sub, err := clifford.BuildHelpWithParent(&target, "serve", &target.Serve, true, clifford.WithColor(false))
+	assert.Nil(t, err)
+	assert.False(t, strings.Contains(sub, "\x1b["))
+}
+
+func TestBuildHelp_VersionShortTaken(t *testing.T) {
+	target := struct {
+		clifford.Clifford `name:"tool" version:"1.2.3"`
+
+		Verbose struct {
+			Value             bool
+			clifford.Clifford `short:"v" long:"verbose" desc:"Enable verbose output"`
+		}
+	}{}
+
+	t.Setenv("NO_COLOR", "1")
+	help, err := clifford.BuildHelp(&target, false)
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(help, "-v, --verbose"))
+	assert.True(t, strings.Contains(help, "  --version"))
+	assert.False(t, strings.Contains(help, "-v, --version"))
+	assert.Equal(t, strings.Count(help, "-v,"), 1)
+
+	// The same holds for an embedded Version
+	embedded := struct {
+		clifford.Clifford `name:"tool"`
+		clifford.Version  `version:"1.2.3"`
+
+		Verbose bool `short:"v" long:"verbose"`
+	}{}
+	help, err = clifford.BuildHelp(&embedded, false)
+	assert.Nil(t, err)
+	assert.False(t, strings.Contains(help, "-v, --version"))
+}
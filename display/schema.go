@@ -0,0 +1,174 @@
+package display
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/chriso345/clifford/errors"
+	"github.com/chriso345/clifford/internal/common"
+)
+
+// jsonSchemaDraft is the JSON Schema dialect emitted by BuildJSONSchema.
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// BuildJSONSchema returns a JSON Schema describing the options accepted by the
+// CLI defined by target.
+//
+// Each flag or argument becomes a property keyed by its long name, or by its
+// lowercased field name when it has none, carrying its JSON type, description,
+// default and any `choices` as an enum. Required fields are listed under
+// `required`, and subcommands are nested as object properties of their own.
+func BuildJSONSchema(target any) (string, error) {
+	if !common.IsStructPtr(target) {
+		return "", errors.NewParseError("invalid type: must pass pointer to struct")
+	}
+
+	t := common.GetStructType(target)
+	schema := commandSchema(t)
+	schema["$schema"] = jsonSchemaDraft
+	if name := rootTag(t, "name"); name != "" {
+		schema["title"] = name
+	}
+	if d := topLevelDescription(target); d != "" {
+		schema["description"] = d
+	}
+
+	out, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// commandSchema builds the object schema for the command struct t.
+func commandSchema(t reflect.Type) map[string]any {
+	properties := map[string]any{}
+	var required []string
+	add := func(fieldName string, typ reflect.Type, tags map[string]string) {
+		key := tags["long"]
+		if key == "" {
+			key = strings.ToLower(fieldName)
+		}
+		properties[key] = propertySchema(typ, tags)
+		if tags["required"] == "true" {
+			required = append(required, key)
+		}
+	}
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		switch field.Type.Name() {
+		case "Clifford", "Version", "Help":
+			continue
+		}
+
+		if field.Type.Kind() != reflect.Struct {
+			if !field.Anonymous {
+				add(field.Name, field.Type, common.InlineTags(field))
+			}
+			continue
+		}
+
+		tags := common.GetTagsFromEmbedded(field.Type, field.Name)
+		if tags["subcmd"] == "true" {
+			name := tags["name"]
+			if name == "" {
+				name = strings.ToLower(field.Name)
+			}
+			sub := commandSchema(field.Type)
+			if tags["desc"] != "" {
+				sub["description"] = tags["desc"]
+			}
+			properties[name] = sub
+			continue
+		}
+		valField, ok := field.Type.FieldByName("Value")
+		if !ok {
+			continue
+		}
+		add(field.Name, valField.Type, tags)
+	}
+
+	schema := map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// propertySchema describes a single value of type typ annotated with tags.
+func propertySchema(typ reflect.Type, tags map[string]string) map[string]any {
+	prop := map[string]any{}
+	if jsonType := schemaType(typ); jsonType != "" {
+		prop["type"] = jsonType
+	}
+	switch typ.Kind() {
+	case reflect.Map:
+		if elem := schemaType(typ.Elem()); elem != "" {
+			prop["additionalProperties"] = map[string]any{"type": elem}
+		}
+	case reflect.Slice, reflect.Array:
+		if elem := schemaType(typ.Elem()); elem != "" {
+			prop["items"] = map[string]any{"type": elem}
+		}
+	}
+	if d := tags["desc"]; d != "" {
+		prop["description"] = d
+	}
+	if d := tags["default"]; d != "" {
+		prop["default"] = schemaValue(typ, d)
+	}
+	if c := tags["choices"]; c != "" {
+		var enum []any
+		for _, choice := range common.ParseChoices(c) {
+			enum = append(enum, schemaValue(typ, choice.Value))
+		}
+		prop["enum"] = enum
+	}
+	return prop
+}
+
+// schemaType maps a Go type to its JSON Schema type name.
+func schemaType(typ reflect.Type) string {
+	switch typ.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	}
+	return ""
+}
+
+// schemaValue converts the literal s to the JSON value matching typ, keeping
+// it as a string when it does not convert.
+func schemaValue(typ reflect.Type, s string) any {
+	switch schemaType(typ) {
+	case "boolean":
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+	case "integer":
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return n
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	}
+	return s
+}
@@ -0,0 +1,86 @@
+package display_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/chriso345/gore/assert"
+
+	"github.com/chriso345/clifford"
+)
+
+func TestBuildJSONSchema_Types(t *testing.T) {
+	target := struct {
+		clifford.Clifford `name:"app" desc:"Sample app"`
+		clifford.Help
+
+		File struct {
+			Value string
+			clifford.Required
+		}
+		Port struct {
+			Value             int `default:"8080"`
+			clifford.Clifford `long:"port" desc:"Port to listen on"`
+		}
+		Level struct {
+			Value             string
+			clifford.Clifford `long:"log-level" choices:"debug,info,warn"`
+		}
+		Verbose bool `short:"v" long:"verbose"`
+
+		Serve struct {
+			clifford.Subcommand `name:"serve"`
+			Ratio               struct {
+				Value             float64
+				clifford.Clifford `long:"ratio"`
+			}
+		}
+	}{}
+
+	out, err := clifford.BuildJSONSchema(&target)
+	assert.Nil(t, err)
+
+	var schema struct {
+		Schema     string                     `json:"$schema"`
+		Title      string                     `json:"title"`
+		Type       string                     `json:"type"`
+		Required   []string                   `json:"required"`
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+	assert.Nil(t, json.Unmarshal([]byte(out), &schema))
+	assert.Equal(t, schema.Title, "app")
+	assert.Equal(t, schema.Type, "object")
+	assert.Equal(t, len(schema.Required), 1)
+	assert.Equal(t, schema.Required[0], "file")
+
+	type property struct {
+		Type        string                     `json:"type"`
+		Description string                     `json:"description"`
+		Default     any                        `json:"default"`
+		Enum        []string                   `json:"enum"`
+		Properties  map[string]json.RawMessage `json:"properties"`
+	}
+	prop := func(raw json.RawMessage) property {
+		var p property
+		assert.Nil(t, json.Unmarshal(raw, &p))
+		return p
+	}
+
+	assert.Equal(t, prop(schema.Properties["file"]).Type, "string")
+
+	port := prop(schema.Properties["port"])
+	assert.Equal(t, port.Type, "integer")
+	assert.Equal(t, port.Description, "Port to listen on")
+	assert.Equal(t, port.Default, any(8080.0))
+
+	level := prop(schema.Properties["log-level"])
+	assert.Equal(t, level.Type, "string")
+	assert.Equal(t, len(level.Enum), 3)
+	assert.Equal(t, level.Enum[1], "info")
+
+	assert.Equal(t, prop(schema.Properties["verbose"]).Type, "boolean")
+
+	serve := prop(schema.Properties["serve"])
+	assert.Equal(t, serve.Type, "object")
+	assert.Equal(t, prop(serve.Properties["ratio"]).Type, "number")
+}
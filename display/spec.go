@@ -0,0 +1,130 @@
+package display
+
+import (
+	"reflect"
+
+	"github.com/chriso345/clifford/errors"
+	"github.com/chriso345/clifford/internal/common"
+)
+
+// CommandSpec describes a command of a CLI definition: the root or one of its
+// subcommands. It holds only plain values, so it can be rendered or serialized
+// without reflection. Hidden commands and flags are included and marked.
+type CommandSpec struct {
+	Name        string
+	Aliases     []string
+	Description string
+	Hidden      bool
+	Flags       []FlagSpec
+	Args        []ArgSpec
+	Subcommands []CommandSpec
+}
+
+// FlagSpec describes a flag. Short and Long are given without their dashes,
+// and Type is the Go type of the field, such as "int" or "[]string".
+type FlagSpec struct {
+	Field       string
+	Short       string
+	Long        string
+	Type        string
+	Default     string
+	Required    bool
+	Description string
+	Env         string
+	Choices     []string
+	Hidden      bool
+}
+
+// ArgSpec describes a positional argument, in the order it is bound.
+type ArgSpec struct {
+	Name        string
+	Type        string
+	Default     string
+	Required    bool
+	Description string
+}
+
+// Describe returns the command tree of the CLI defined by target. The automatic
+// help and version flags are not listed among the flags.
+func Describe(target any) (*CommandSpec, error) {
+	if !common.IsStructPtr(target) {
+		return nil, errors.NewParseError("invalid type: must pass pointer to struct")
+	}
+	t := common.GetStructType(target)
+	spec := commandSpec(t)
+	spec.Name = rootTag(t, "name")
+	spec.Description = topLevelDescription(target)
+	return &spec, nil
+}
+
+// commandSpec describes the command struct t and, recursively, its subcommands.
+func commandSpec(t reflect.Type) CommandSpec {
+	var c CommandSpec
+	add := func(fieldName string, typ reflect.Type, tags map[string]string) {
+		if !common.IsFlag(tags) {
+			c.Args = append(c.Args, ArgSpec{
+				Name:        fieldName,
+				Type:        typ.String(),
+				Default:     tags["default"],
+				Required:    tags["required"] == "true",
+				Description: tags["desc"],
+			})
+			return
+		}
+		var choices []string
+		for _, choice := range common.ParseChoices(tags["choices"]) {
+			choices = append(choices, choice.Value)
+		}
+		c.Flags = append(c.Flags, FlagSpec{
+			Field:       fieldName,
+			Short:       tags["short"],
+			Long:        tags["long"],
+			Type:        typ.String(),
+			Default:     tags["default"],
+			Required:    tags["required"] == "true",
+			Description: tags["desc"],
+			Env:         tags["env"],
+			Choices:     choices,
+			Hidden:      tags["hidden"] == "true",
+		})
+	}
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		switch field.Type.Name() {
+		case "Clifford", "Version", "Help":
+			continue
+		}
+
+		if field.Type.Kind() != reflect.Struct {
+			if !field.Anonymous {
+				add(field.Name, field.Type, common.InlineTags(field))
+			}
+			continue
+		}
+
+		tags := common.GetTagsFromEmbedded(field.Type, field.Name)
+		if tags["subcmd"] == "true" {
+			sub := commandSpec(field.Type)
+			sub.Name = common.SubcommandName(tags, field.Name)
+			sub.Aliases = common.SubcommandAliases(tags)
+			sub.Description = tags["desc"]
+			sub.Hidden = tags["hidden"] == "true"
+			c.Subcommands = append(c.Subcommands, sub)
+			continue
+		}
+		value, ok := field.Type.FieldByName("Value")
+		if !ok {
+			continue
+		}
+		add(field.Name, value.Type, tags)
+		for j := 0; j < field.Type.NumField(); j++ {
+			inner := field.Type.Field(j)
+			if inner.Anonymous || inner.Name == "Value" || inner.Type.Kind() == reflect.Struct {
+				continue
+			}
+			add(field.Name+"."+inner.Name, inner.Type, common.InlineTags(inner))
+		}
+	}
+	return c
+}
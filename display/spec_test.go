@@ -0,0 +1,96 @@
+package display_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/chriso345/gore/assert"
+
+	"github.com/chriso345/clifford"
+)
+
+func TestDescribe(t *testing.T) {
+	target := struct {
+		clifford.Clifford `name:"git" desc:"Version control"`
+		clifford.Help
+
+		Verbose bool `short:"v" long:"verbose" desc:"Verbose output"`
+		Dir     struct {
+			Value             string `default:"."`
+			clifford.Clifford `short:"C" long:"dir" env:"GIT_DIR"`
+		}
+
+		Remote struct {
+			clifford.Subcommand `aliases:"r"`
+			clifford.Desc       `desc:"Manage remotes"`
+
+			Add struct {
+				clifford.Subcommand
+				Name struct {
+					Value string
+					clifford.Required
+				}
+				Mode string `long:"mode" choices:"fetch,push"`
+				Raw  bool   `long:"raw" hidden:"true"`
+			}
+		}
+	}{}
+
+	spec, err := clifford.Describe(&target)
+	assert.Nil(t, err)
+	assert.Equal(t, spec.Name, "git")
+	assert.Equal(t, spec.Description, "Version control")
+
+	assert.Equal(t, len(spec.Flags), 2)
+	assert.Equal(t, spec.Flags[0].Long, "verbose")
+	assert.Equal(t, spec.Flags[0].Type, "bool")
+	dir := spec.Flags[1]
+	assert.Equal(t, dir.Short, "C")
+	assert.Equal(t, dir.Type, "string")
+	assert.Equal(t, dir.Default, ".")
+	assert.Equal(t, dir.Env, "GIT_DIR")
+
+	assert.Equal(t, len(spec.Subcommands), 1)
+	remote := spec.Subcommands[0]
+	assert.Equal(t, remote.Name, "remote")
+	assert.Equal(t, remote.Aliases[0], "r")
+	assert.Equal(t, remote.Description, "Manage remotes")
+
+	add := remote.Subcommands[0]
+	assert.Equal(t, add.Name, "add")
+	assert.Equal(t, len(add.Args), 1)
+	assert.Equal(t, add.Args[0].Name, "Name")
+	assert.True(t, add.Args[0].Required)
+	assert.Equal(t, len(add.Flags[0].Choices), 2)
+	assert.Equal(t, add.Flags[0].Choices[1], "push")
+	assert.False(t, add.Flags[0].Hidden)
+	assert.True(t, add.Flags[1].Hidden)
+
+	_, err = clifford.Describe(target)
+	assert.NotNil(t, err)
+}
+
+func TestDescribe_InlineTagsMatchParser(t *testing.T) {
+	target := struct {
+		clifford.Clifford `name:"app"`
+
+		Cache bool   `pair:"with,without"`
+		Trace bool   `long:"trace" hidden:"true"`
+		Out   string `complete:"file"`
+	}{}
+
+	spec, err := clifford.Describe(&target)
+	assert.Nil(t, err)
+	assert.Equal(t, len(spec.Flags), 2)
+	assert.Equal(t, spec.Flags[0].Field, "Cache")
+	assert.True(t, spec.Flags[1].Hidden)
+	assert.Equal(t, len(spec.Args), 1)
+
+	var buf bytes.Buffer
+	assert.Nil(t, clifford.GenBashCompletion(&target, &buf))
+	script := buf.String()
+	assert.True(t, strings.Contains(script, `compgen -W "--with-cache --without-cache"`))
+	assert.False(t, strings.Contains(script, "--trace"))
+	assert.True(t, strings.Contains(script, "compgen -f"))
+}
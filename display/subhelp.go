@@ -2,13 +2,16 @@ package display
 
 import (
 	"fmt"
-	"github.com/chriso345/clifford/internal/common"
 	"strings"
+
+	"github.com/chriso345/clifford/internal/common"
+	"github.com/chriso345/clifford/internal/options"
 )
 
 // BuildHelpWithParent builds help for a subcommand while showing the parent application name
-// and the subcommand name together (e.g. "app server [OPTIONS]").
-func BuildHelpWithParent(parent any, subName string, subTarget any, long bool) (string, error) {
+// and the subcommand name together (e.g. "app server [OPTIONS]"). For nested subcommands,
+// subName is the full space-separated path below parent (e.g. "remote add").
+func BuildHelpWithParent(parent any, subName string, subTarget any, long bool, opts ...options.Option) (string, error) {
 	if !common.IsStructPtr(subTarget) {
 		return "", fmt.Errorf("invalid type: must pass pointer to struct")
 	}
@@ -29,31 +32,43 @@ func BuildHelpWithParent(parent any, subName string, subTarget any, long bool) (
 		parentName = "<app>"
 	}
 
+	cfg := options.New(opts...)
 	fullName := parentName + " " + subName
 
 	var builder strings.Builder
-	builder.WriteString(ansiHelp("Usage:", ansiBold, ansiUnderline) + " ")
-	builder.WriteString(ansiHelp(fullName, ansiBold))
-
-	// required args for subTarget
-	requiredArgs := getRequiredArgs(subTarget)
-	for _, arg := range requiredArgs {
-		builder.WriteString(fmt.Sprintf(" <%s>", strings.ToUpper(arg)))
-	}
-	if hasOptions(subTarget) {
-		builder.WriteString(" [OPTIONS]")
+	builder.WriteString(ansiHelp(cfg, cfg.Translate("help.usage", "Usage")+":", ansiBold, ansiUnderline) + " ")
+	if usage := commandTag(subTarget, "usage"); usage != "" {
+		// A `usage` tag replaces the generated synopsis verbatim
+		builder.WriteString(usage)
+	} else {
+		builder.WriteString(ansiHelp(cfg, fullName, ansiBold))
+		// required args for subTarget
+		for _, arg := range getRequiredArgs(subTarget) {
+			builder.WriteString(fmt.Sprintf(" <%s>", strings.ToUpper(arg)))
+		}
+		if hasOptions(subTarget) {
+			builder.WriteString(" [OPTIONS]")
+		}
 	}
 	builder.WriteString("\n")
 
 	// description from subTarget (Desc embedded)
 	if d := topLevelDescription(subTarget); d != "" {
-		builder.WriteString("\n" + d + "\n")
+		builder.WriteString("\n" + cfg.Translate("desc", d) + "\n")
+	}
+	level := cfg.Verbosity(long)
+	if about := commandTag(subTarget, "long_about"); about != "" && level >= options.HelpFull {
+		builder.WriteString("\n" + strings.Join(wrapText(cfg.Translate("long_about", about), wrapWidth(cfg)), "\n") + "\n")
 	}
 
 	if hasOptions(subTarget) {
-		builder.WriteString("\n" + ansiHelp("Options:", ansiBold, ansiUnderline) + "\n")
+		builder.WriteString("\n" + ansiHelp(cfg, cfg.Translate("help.options", "Options")+":", ansiBold, ansiUnderline) + "\n")
 		// For subcommand help, show options from subTarget; decide whether to include -h/-v based on parent Clifford tags
-		builder.WriteString(optionsHelp(subTarget))
+		builder.WriteString(optionsHelp(subTarget, cfg, level))
+	}
+
+	if level >= options.HelpFull {
+		builder.WriteString(examplesHelp(subTarget, cfg))
 	}
 
 	return builder.String(), nil
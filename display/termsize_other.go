@@ -0,0 +1,7 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package display
+
+// terminalWidth reports no terminal width on platforms where it is not
+// detected, so help falls back to COLUMNS or 80 columns.
+func terminalWidth() int { return 0 }
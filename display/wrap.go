@@ -0,0 +1,60 @@
+package display
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/chriso345/clifford/internal/options"
+)
+
+// helpWidth is the column at which option descriptions are wrapped by default.
+const helpWidth = 80
+
+// wrapWidth returns the column at which help is wrapped under cfg: the
+// WithWidth setting, then the COLUMNS environment variable, then the width of
+// the terminal on stdout, and finally helpWidth.
+func wrapWidth(cfg *options.Config) int {
+	if cfg.Width > 0 {
+		return cfg.Width
+	}
+	if cols, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && cols > 0 {
+		return cols
+	}
+	if cols := terminalWidth(); cols > 0 {
+		return cols
+	}
+	return helpWidth
+}
+
+// minDescWidth is the narrowest description column wrapping will produce.
+const minDescWidth = 20
+
+// wrapText breaks text into lines of at most width columns. Lines are only
+// broken at spaces, never inside a word: tokens longer than width (such as
+// URLs) overflow onto their own line instead. Existing newlines are kept as
+// hard breaks, and the leading indentation of each hard line is repeated on
+// its continuation lines.
+func wrapText(text string, width int) []string {
+	width = max(width, minDescWidth)
+	var lines []string
+	for _, hard := range strings.Split(text, "\n") {
+		indent := hard[:len(hard)-len(strings.TrimLeft(hard, " \t"))]
+		words := strings.Fields(hard)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+		line := indent + words[0]
+		for _, w := range words[1:] {
+			if len(line)+1+len(w) > width {
+				lines = append(lines, line)
+				line = indent + w
+				continue
+			}
+			line += " " + w
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -3,13 +3,30 @@ package errors
 import (
 	stderrors "errors"
 	"fmt"
+	"strings"
 )
 
 var (
 	ErrParse                = stderrors.New("parse error")
 	ErrMissingArg           = stderrors.New("missing argument")
 	ErrUnknownSubcommand    = stderrors.New("unknown subcommand")
+	ErrSubcommandRequired   = stderrors.New("subcommand required")
+	ErrUnknownFlag          = stderrors.New("unknown flag")
+	ErrFlagConstraint       = stderrors.New("flag constraint violated")
+	ErrTooManyArgs          = stderrors.New("too many arguments")
 	ErrUnsupportedFieldType = stderrors.New("unsupported field type")
+	ErrInvalidValue         = stderrors.New("invalid value")
+	ErrInvalidChoice        = stderrors.New("invalid choice")
+	ErrOutOfRange           = stderrors.New("value out of range")
+	ErrPatternMismatch      = stderrors.New("pattern mismatch")
+	ErrValidation           = stderrors.New("validation failed")
+	ErrDefinition           = stderrors.New("invalid definition")
+
+	// ErrHelpRequested and ErrVersionRequested are returned by pure parses in
+	// place of printing help or version output and exiting, and by parses
+	// whose WithExitFunc function returns after the output is printed.
+	ErrHelpRequested    = stderrors.New("help requested")
+	ErrVersionRequested = stderrors.New("version requested")
 )
 
 // ParseError represents a generic parsing error produced by the CLI parser.
@@ -19,23 +36,115 @@ type ParseError struct{ Msg string }
 func (e ParseError) Error() string { return e.Msg }
 
 // MissingArgError indicates a required positional or flag was not provided.
-type MissingArgError struct{ Field string }
+// Condition, if present, names the flag whose presence made the field required.
+type MissingArgError struct{ Field, Condition string }
 
 func (e MissingArgError) Error() string {
+	if e.Condition != "" {
+		return fmt.Sprintf("missing required argument: %s (required when %s is given)", e.Field, e.Condition)
+	}
 	return fmt.Sprintf("missing required argument: %s", e.Field)
 }
 
 // UnknownSubcommandError indicates the user invoked a subcommand that does not exist.
-// Suggestion, if present, is a close match the user may have intended.
-type UnknownSubcommandError struct{ Name, Suggestion string }
+// Suggestion, if present, is a close match the user may have intended. Available,
+// if present, lists every valid subcommand at that level.
+type UnknownSubcommandError struct {
+	Name, Suggestion string
+	Available        []string
+}
 
 func (e UnknownSubcommandError) Error() string {
+	msg := fmt.Sprintf("unknown subcommand: %s", e.Name)
 	if e.Suggestion != "" {
-		return fmt.Sprintf("unknown subcommand: %s (did you mean %q?)", e.Name, e.Suggestion)
+		msg += fmt.Sprintf(" (did you mean %q?)", e.Suggestion)
+	}
+	if len(e.Available) > 0 {
+		msg += "; available: " + strings.Join(e.Available, ", ")
+	}
+	return msg
+}
+
+// SubcommandRequiredError indicates a command tagged `require_subcmd:"true"`
+// was invoked without a subcommand. Command is the path of that command, empty
+// for the root, and Available lists its subcommands. It matches
+// ErrSubcommandRequired with errors.Is.
+type SubcommandRequiredError struct {
+	Command   string
+	Available []string
+}
+
+func (e SubcommandRequiredError) Error() string {
+	msg := "missing subcommand"
+	if e.Command != "" {
+		msg += fmt.Sprintf(" for %s", e.Command)
+	}
+	if len(e.Available) > 0 {
+		msg += "; available: " + strings.Join(e.Available, ", ")
 	}
-	return fmt.Sprintf("unknown subcommand: %s", e.Name)
+	return msg
+}
+
+func (e SubcommandRequiredError) Is(target error) bool { return target == ErrSubcommandRequired }
+
+// UnknownFlagError indicates the user passed a flag the command does not declare.
+// Suggestion, if present, is a close match the user may have intended. It
+// matches ErrUnknownFlag with errors.Is.
+type UnknownFlagError struct{ Name, Suggestion string }
+
+func (e UnknownFlagError) Error() string {
+	msg := fmt.Sprintf("unknown flag: %s", e.Name)
+	if e.Suggestion != "" {
+		msg += fmt.Sprintf(" (did you mean %q?)", e.Suggestion)
+	}
+	return msg
+}
+
+func (e UnknownFlagError) Is(target error) bool { return target == ErrUnknownFlag }
+
+// FlagConstraintError indicates Flag was given without Other, which it
+// requires, or, when Conflict is set, together with Other, which it conflicts
+// with. It matches ErrFlagConstraint with errors.Is.
+type FlagConstraintError struct {
+	Flag, Other string
+	Conflict    bool
 }
 
+func (e FlagConstraintError) Error() string {
+	if e.Conflict {
+		return fmt.Sprintf("%s conflicts with %s", e.Flag, e.Other)
+	}
+	return fmt.Sprintf("%s requires %s", e.Flag, e.Other)
+}
+
+func (e FlagConstraintError) Is(target error) bool { return target == ErrFlagConstraint }
+
+// TooManyArgsError indicates more positional arguments were given than the
+// command declares. Extra lists the ones left over. It matches ErrTooManyArgs
+// with errors.Is.
+type TooManyArgsError struct{ Extra []string }
+
+func (e TooManyArgsError) Error() string {
+	return "too many arguments: unexpected " + strings.Join(e.Extra, " ")
+}
+
+func (e TooManyArgsError) Is(target error) bool { return target == ErrTooManyArgs }
+
+// MultiError holds every error found in a command when parsing with
+// WithAllErrors. Its message lists them one per line, and errors.As and
+// errors.Is match any of them.
+type MultiError struct{ Errors []error }
+
+func (e MultiError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func (e MultiError) Unwrap() []error { return e.Errors }
+
 // UnsupportedFieldTypeError indicates the CLI contains an unsupported field type.
 type UnsupportedFieldTypeError struct{ Field, Type string }
 
@@ -43,12 +152,166 @@ func (e UnsupportedFieldTypeError) Error() string {
 	return fmt.Sprintf("unsupported type for field %s: %s", e.Field, e.Type)
 }
 
+// InvalidValueError indicates a value given for a field could not be converted
+// to the field's type. Value is the offending token and Kind the type it was
+// expected to convert to, such as "int" or "time.Duration". It matches
+// ErrInvalidValue with errors.Is.
+type InvalidValueError struct{ Field, Value, Kind string }
+
+func (e InvalidValueError) Error() string {
+	return fmt.Sprintf("invalid value for %s: %q is not a valid %s", e.Field, e.Value, e.Kind)
+}
+
+func (e InvalidValueError) Is(target error) bool { return target == ErrInvalidValue }
+
+// InvalidChoiceError indicates a value given for a field is not one of the
+// values allowed by its `choices` tag, which are listed in Choices. It
+// matches ErrInvalidChoice with errors.Is.
+type InvalidChoiceError struct {
+	Field, Value string
+	Choices      []string
+}
+
+func (e InvalidChoiceError) Error() string {
+	return fmt.Sprintf("invalid value for %s: %q (choices: %s)", e.Field, e.Value, strings.Join(e.Choices, ", "))
+}
+
+func (e InvalidChoiceError) Is(target error) bool { return target == ErrInvalidChoice }
+
+// RangeError indicates a numeric value lies outside the bounds declared by the
+// `min` and `max` tags of its field. Bound is the violated tag, "min" or
+// "max", and Limit its value. It matches ErrOutOfRange with errors.Is.
+type RangeError struct{ Field, Value, Bound, Limit string }
+
+func (e RangeError) Error() string {
+	if e.Bound == "min" {
+		return fmt.Sprintf("invalid value for %s: %s is below the minimum of %s", e.Field, e.Value, e.Limit)
+	}
+	return fmt.Sprintf("invalid value for %s: %s is above the maximum of %s", e.Field, e.Value, e.Limit)
+}
+
+func (e RangeError) Is(target error) bool { return target == ErrOutOfRange }
+
+// PatternError indicates a string value does not match the regular expression
+// declared by the `pattern` tag of its field. It matches ErrPatternMismatch
+// with errors.Is.
+type PatternError struct{ Field, Value, Pattern string }
+
+func (e PatternError) Error() string {
+	return fmt.Sprintf("invalid value for %s: %q does not match %s", e.Field, e.Value, e.Pattern)
+}
+
+func (e PatternError) Is(target error) bool { return target == ErrPatternMismatch }
+
+// ValidationError reports that a command-level validation hook rejected the
+// parsed values. Command is the space-separated subcommand path, empty for the
+// root command, and Err is the error returned by the hook. It matches
+// ErrValidation with errors.Is and unwraps to Err.
+type ValidationError struct {
+	Command string
+	Err     error
+}
+
+func (e ValidationError) Error() string {
+	if e.Command == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %s", e.Command, e.Err)
+}
+func (e ValidationError) Is(target error) bool { return target == ErrValidation }
+func (e ValidationError) Unwrap() error        { return e.Err }
+
+// DefinitionError indicates the CLI definition struct itself is malformed.
+// Unlike the other errors it reports a programming mistake rather than bad user input.
+// It matches ErrDefinition with errors.Is.
+type DefinitionError struct{ Msg string }
+
+func (e DefinitionError) Error() string        { return "invalid CLI definition: " + e.Msg }
+func (e DefinitionError) Is(target error) bool { return target == ErrDefinition }
+
+// TranslatedError carries a localized message for an underlying error. It unwraps
+// to the original error, so errors.Is and errors.As continue to match.
+type TranslatedError struct {
+	Err error
+	Msg string
+}
+
+func (e TranslatedError) Error() string { return e.Msg }
+func (e TranslatedError) Unwrap() error { return e.Err }
+
+// Key returns a stable identifier for err, used to look up its translation.
+func Key(err error) string {
+	switch {
+	case stderrors.As(err, new(MissingArgError)):
+		return "error.missing_arg"
+	case stderrors.As(err, new(UnknownSubcommandError)):
+		return "error.unknown_subcommand"
+	case stderrors.As(err, new(SubcommandRequiredError)):
+		return "error.subcommand_required"
+	case stderrors.As(err, new(UnknownFlagError)):
+		return "error.unknown_flag"
+	case stderrors.As(err, new(FlagConstraintError)):
+		return "error.flag_constraint"
+	case stderrors.As(err, new(TooManyArgsError)):
+		return "error.too_many_args"
+	case stderrors.As(err, new(UnsupportedFieldTypeError)):
+		return "error.unsupported_field_type"
+	case stderrors.As(err, new(InvalidValueError)):
+		return "error.invalid_value"
+	case stderrors.As(err, new(InvalidChoiceError)):
+		return "error.invalid_choice"
+	case stderrors.As(err, new(RangeError)):
+		return "error.out_of_range"
+	case stderrors.As(err, new(PatternError)):
+		return "error.pattern"
+	case stderrors.As(err, new(ValidationError)):
+		return "error.validation"
+	case stderrors.As(err, new(DefinitionError)):
+		return "error.definition"
+	default:
+		return "error.parse"
+	}
+}
+
 // Helper constructors
 func NewParseError(msg string) error   { return ParseError{Msg: msg} }
 func NewMissingArg(field string) error { return MissingArgError{Field: field} }
+func NewConditionalMissingArg(field, condition string) error {
+	return MissingArgError{Field: field, Condition: condition}
+}
 func NewUnknownSubcommand(name, suggestion string) error {
 	return UnknownSubcommandError{Name: name, Suggestion: suggestion}
 }
+func NewSubcommandRequired(command string, available []string) error {
+	return SubcommandRequiredError{Command: command, Available: available}
+}
+func NewUnknownFlag(name, suggestion string) error {
+	return UnknownFlagError{Name: name, Suggestion: suggestion}
+}
+func NewFlagConstraint(flag, other string, conflict bool) error {
+	return FlagConstraintError{Flag: flag, Other: other, Conflict: conflict}
+}
+func NewMultiError(errs []error) error    { return MultiError{Errors: errs} }
+func NewTooManyArgs(extra []string) error { return TooManyArgsError{Extra: extra} }
 func NewUnsupportedField(field, typ string) error {
 	return UnsupportedFieldTypeError{Field: field, Type: typ}
 }
+func NewInvalidValue(field, value, kind string) error {
+	return InvalidValueError{Field: field, Value: value, Kind: kind}
+}
+func NewInvalidChoice(field, value string, choices []string) error {
+	return InvalidChoiceError{Field: field, Value: value, Choices: choices}
+}
+func NewRangeError(field, value, bound, limit string) error {
+	return RangeError{Field: field, Value: value, Bound: bound, Limit: limit}
+}
+func NewPatternError(field, value, pattern string) error {
+	return PatternError{Field: field, Value: value, Pattern: pattern}
+}
+func NewValidationError(command string, err error) error {
+	return ValidationError{Command: command, Err: err}
+}
+func NewDefinitionError(msg string) error { return DefinitionError{Msg: msg} }
+func NewTranslatedError(err error, msg string) error {
+	return TranslatedError{Err: err, Msg: msg}
+}
@@ -208,7 +208,7 @@ func ExampleBuildHelp() {
 		}
 	}{}
 
-	help, err := clifford.BuildHelp(&target, false)
+	help, err := clifford.BuildHelp(&target, true)
 	if err != nil {
 		panic(err)
 	}
@@ -334,7 +334,7 @@ func Example_help_as_subcommand() {
 	}{}
 
 	sub := parent.Serve
-	help, err := clifford.BuildHelpWithParent(&parent, "serve", &sub, false)
+	help, err := clifford.BuildHelpWithParent(&parent, "serve", &sub, true)
 	if err != nil {
 		panic(err)
 	}
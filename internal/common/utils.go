@@ -5,9 +5,33 @@ import (
 	"strings"
 )
 
+// fieldTagKeys lists the metadata keys accepted both on embedded markers and
+// directly on fields.
+var fieldTagKeys = []string{"expand_home", "fromfile", "complete", "secret", "required_if", "requires", "conflicts", "pos", "kv_separator", "separator", "sep", "example", "choices", "choices_ci", "min", "max", "pattern", "count", "pair", "env", "rest_positional", "rest_required", "assignment", "since", "global", "persistent", "hidden"}
+
+// inlineTagKeys lists the metadata keys read from an inline field, such as
+// MaxItems int `short:"n" long:"max-items"`.
+var inlineTagKeys = append([]string{"default", "desc", "required", "short", "long"}, fieldTagKeys...)
+
+// InlineTags collects the metadata declared directly on an inline field.
+// Every reader of inline fields uses it, so they all accept the same tags.
+func InlineTags(field reflect.StructField) map[string]string {
+	tags := make(map[string]string)
+	for _, key := range inlineTagKeys {
+		if val := field.Tag.Get(key); val != "" {
+			tags[key] = val
+		}
+	}
+	return tags
+}
+
 // GetTagsFromEmbedded retrieves tags from embedded structs in the target struct.
+//
+// A `desc` declared directly on a marker such as Clifford takes precedence over
+// one supplied through an embedded Desc, regardless of field order.
 func GetTagsFromEmbedded(t reflect.Type, fieldName string) map[string]string {
 	tags := make(map[string]string)
+	embeddedDesc := ""
 
 	for i := range t.NumField() {
 		field := t.Field(i)
@@ -21,11 +45,14 @@ func GetTagsFromEmbedded(t reflect.Type, fieldName string) map[string]string {
 			case "Required":
 				tags["required"] = "true"
 			case "Desc":
-				if val := field.Tag.Get("desc"); val != "" {
-					tags["desc"] = val
-				}
+				embeddedDesc = field.Tag.Get("desc")
 			case "Subcommand":
 				tags["subcmd"] = "true"
+				for _, key := range []string{"name", "group", "aliases", "hidden"} {
+					if val := field.Tag.Get(key); val != "" {
+						tags[key] = val
+					}
+				}
 			case "Help":
 				// Allow specifying how help is exposed: type:"flag"|"subcmd"|"both" or help:"..."
 				if val := field.Tag.Get("type"); val != "" {
@@ -34,7 +61,7 @@ func GetTagsFromEmbedded(t reflect.Type, fieldName string) map[string]string {
 					tags["help"] = val
 				}
 			default:
-				for _, key := range []string{"short", "long", "desc", "required", "subcmd"} {
+				for _, key := range append([]string{"short", "long", "desc", "required", "subcmd", "aliases"}, fieldTagKeys...) {
 					if val := field.Tag.Get(key); val != "" {
 						tags[key] = val
 					}
@@ -43,17 +70,59 @@ func GetTagsFromEmbedded(t reflect.Type, fieldName string) map[string]string {
 			continue
 		}
 
-		// Also allow metadata to be provided directly on non-anonymous fields (e.g. default values).
-		for _, key := range []string{"default", "desc", "required", "short", "long", "subcmd", "help"} {
+		// Also allow metadata to be provided directly on the Value field (e.g.
+		// default values). Other named fields are inline flags with their own tags.
+		if field.Name != "Value" {
+			continue
+		}
+		for _, key := range append([]string{"default", "desc", "required", "short", "long", "subcmd", "help"}, fieldTagKeys...) {
 			if val := field.Tag.Get(key); val != "" {
 				tags[key] = val
 			}
 		}
 	}
 
+	if tags["desc"] == "" && embeddedDesc != "" {
+		tags["desc"] = embeddedDesc
+	}
 	return tags
 }
 
+// SubcommandName returns the name a subcommand is invoked by: its `name` tag,
+// or the lowercased field name when there is none.
+func SubcommandName(tags map[string]string, fieldName string) string {
+	if name := tags["name"]; name != "" {
+		return name
+	}
+	return strings.ToLower(fieldName)
+}
+
+// SubcommandAliases returns the alternative names a subcommand declares with
+// an `aliases` tag, such as `aliases:"co,ck"`.
+func SubcommandAliases(tags map[string]string) []string {
+	var aliases []string
+	for _, alias := range strings.Split(tags["aliases"], ",") {
+		if alias = strings.TrimSpace(alias); alias != "" {
+			aliases = append(aliases, alias)
+		}
+	}
+	return aliases
+}
+
+// IsSubcommandName reports whether arg invokes the subcommand described by
+// tags, by its name or one of its aliases.
+func IsSubcommandName(tags map[string]string, fieldName, arg string) bool {
+	if arg == SubcommandName(tags, fieldName) {
+		return true
+	}
+	for _, alias := range SubcommandAliases(tags) {
+		if arg == alias {
+			return true
+		}
+	}
+	return false
+}
+
 // ArgsIndexOf returns the index of the first occurrence of s in args, or -1 if not found.
 func ArgsIndexOf(args []string, s string) int {
 	for i, arg := range args {
@@ -64,6 +133,15 @@ func ArgsIndexOf(args []string, s string) int {
 	return -1
 }
 
+// Indirect returns the element type of the pointer type t, or t itself when it
+// is not a pointer.
+func Indirect(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Pointer {
+		return t.Elem()
+	}
+	return t
+}
+
 // IsStructPtr checks if the provided value is a pointer to a struct.
 func IsStructPtr(v any) bool {
 	t := reflect.TypeOf(v)
@@ -75,6 +153,16 @@ func GetStructType(v any) reflect.Type {
 	return reflect.TypeOf(v).Elem()
 }
 
+// HasCliffordField reports whether the struct type t embeds a `Clifford` field.
+func HasCliffordField(t reflect.Type) bool {
+	for i := range t.NumField() {
+		if t.Field(i).Type.Name() == "Clifford" {
+			return true
+		}
+	}
+	return false
+}
+
 // MetaArgEnabled returns true if the root struct has a `Clifford` field with tag or name matching s
 // or if the field name itself matches s.
 func MetaArgEnabled(s string, target any) bool {
@@ -104,3 +192,52 @@ func MetaArgEnabled(s string, target any) bool {
 
 	return false
 }
+
+// PairFlags returns the two long flags generated for a boolean tagged
+// `pair:"enable,disable"`, such as --enable-color and --disable-color. The
+// suffix is the long name, or the lowercased field name when there is none.
+func PairFlags(tags map[string]string, fieldName string) (on, off string, ok bool) {
+	onPrefix, offPrefix, found := strings.Cut(tags["pair"], ",")
+	if !found || onPrefix == "" || offPrefix == "" {
+		return "", "", false
+	}
+	name := tags["long"]
+	if name == "" {
+		name = strings.ToLower(fieldName)
+	}
+	return "--" + onPrefix + "-" + name, "--" + offPrefix + "-" + name, true
+}
+
+// NegatedFlag returns the `--no-` form of a boolean's long flag, such as
+// --no-color for `long:"color"`, or "" when there is no long flag or the
+// boolean is paired.
+func NegatedFlag(tags map[string]string) string {
+	if tags["long"] == "" || tags["pair"] != "" {
+		return ""
+	}
+	return "--no-" + tags["long"]
+}
+
+// IsFlag reports whether tags describe a flag rather than a positional argument.
+func IsFlag(tags map[string]string) bool {
+	return tags["short"] != "" || tags["long"] != "" || tags["pair"] != ""
+}
+
+// Choice is one allowed value declared by a `choices` tag.
+type Choice struct {
+	Value string
+	Desc  string // optional, given as `value=description`
+}
+
+// ParseChoices splits a `choices` tag such as "debug=verbose logging,info"
+// into its values and their optional descriptions.
+func ParseChoices(tag string) []Choice {
+	var choices []Choice
+	for _, item := range strings.Split(tag, ",") {
+		value, desc, _ := strings.Cut(item, "=")
+		if value = strings.TrimSpace(value); value != "" {
+			choices = append(choices, Choice{Value: value, Desc: strings.TrimSpace(desc)})
+		}
+	}
+	return choices
+}
@@ -0,0 +1,6 @@
+// Package options defines the configuration shared by the parsing and display
+// packages, along with the functional options used to build it.
+//
+// This package is intended for internal use only; the option constructors are
+// re-exported from the root clifford package.
+package options
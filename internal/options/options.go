@@ -0,0 +1,269 @@
+package options
+
+import (
+	"context"
+	"io"
+)
+
+// Config holds the settings applied to a single parse.
+type Config struct {
+	// HelpOnError prints the command help to ErrOutput alongside a parse error.
+	HelpOnError bool
+	// UsageOnMissing prints help and the error, then exits, when the root
+	// command is missing a required argument.
+	UsageOnMissing bool
+	// QuietErrors returns parse errors without any accompanying output. It
+	// takes precedence over HelpOnError.
+	QuietErrors bool
+	// PlusFlags enables `+x` toggles that set the boolean short flag -x to false.
+	PlusFlags bool
+	// JSONStdin reads a JSON object from piped stdin as a low-precedence value source.
+	JSONStdin bool
+	// Output replaces os.Stdout as the destination of help and version text.
+	Output io.Writer
+	// ErrOutput replaces os.Stderr as the destination of the help and errors
+	// printed by HelpOnError and UsageOnMissing.
+	ErrOutput io.Writer
+	// Exit replaces os.Exit when help, version or usage output ends the parse.
+	Exit func(code int)
+	// Stdin replaces os.Stdin as the source read by JSONStdin.
+	Stdin io.Reader
+	// FlagsFile enables the built-in --flags-file flag.
+	FlagsFile bool
+	// ConfigFiles lists JSON config files, later files overriding earlier ones.
+	ConfigFiles []string
+	// Normalizers transform raw values before conversion, keyed by field name.
+	Normalizers map[string]func(string) string
+	// ListCommands includes every valid subcommand in unknown-subcommand errors.
+	ListCommands bool
+	// RootOnlyHelp restricts the help and version flags to the root command.
+	RootOnlyHelp bool
+	// Translator localizes user-visible strings, keyed by a stable identifier.
+	Translator func(key, text string) string
+	// Validators check a command's bound struct, keyed by subcommand path.
+	Validators map[string]func(cmd any) error
+	// BestEffort records conversion failures as warnings instead of failing.
+	BestEffort bool
+	// AllErrors reports every problem found in a command instead of the first.
+	AllErrors bool
+	// RequiredFirst lists required flags before optional ones in help.
+	RequiredFirst bool
+	// Args replaces os.Args[1:] as the arguments parsed when it is non-nil.
+	Args []string
+	// Color forces ANSI styling of help on or off. When nil, help is styled
+	// only when written to a terminal.
+	Color *bool
+	// Width is the column at which help text is wrapped; zero means the
+	// COLUMNS variable or terminal width, falling back to 80.
+	Width int
+	// HelpVerbosity overrides the detail chosen by the long argument of the
+	// help builders when non-zero.
+	HelpVerbosity HelpVerbosity
+	// Context is passed to the Run method of the command ParseAndRun invokes.
+	Context context.Context
+	// SubcommandHint replaces the built-in hint shown below the subcommands in
+	// help, with {name} replaced by the program name. An empty hint is not shown.
+	SubcommandHint *string
+}
+
+// HelpVerbosity selects how much detail help output shows.
+type HelpVerbosity int
+
+const (
+	// HelpTerse lists the names of commands, arguments and flags only.
+	HelpTerse HelpVerbosity = iota + 1
+	// HelpNormal adds descriptions with their defaults, choices and other notes.
+	HelpNormal
+	// HelpFull adds the long_about text, choice tables, environment variables
+	// and examples.
+	HelpFull
+)
+
+// Option configures a Config.
+type Option func(*Config)
+
+// New returns a Config with opts applied in order.
+func New(opts ...Option) *Config {
+	cfg := &Config{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(cfg)
+		}
+	}
+	return cfg
+}
+
+// Verbosity returns the configured help verbosity, falling back to HelpFull
+// when long is set and HelpTerse otherwise.
+func (c *Config) Verbosity(long bool) HelpVerbosity {
+	switch {
+	case c.HelpVerbosity != 0:
+		return c.HelpVerbosity
+	case long:
+		return HelpFull
+	}
+	return HelpTerse
+}
+
+// Translate passes text through the configured Translator, returning it
+// unchanged when none is set.
+func (c *Config) Translate(key, text string) string {
+	if c == nil || c.Translator == nil {
+		return text
+	}
+	return c.Translator(key, text)
+}
+
+// WithHelpOnError prints the help text to stderr whenever parsing fails.
+func WithHelpOnError() Option {
+	return func(c *Config) { c.HelpOnError = true }
+}
+
+// WithUsageOnMissing prints the help text and the error to stderr and exits
+// with status 2 when the root command is missing a required argument.
+func WithUsageOnMissing() Option {
+	return func(c *Config) { c.UsageOnMissing = true }
+}
+
+// WithQuietErrors returns parse errors plainly, without any help or usage
+// output, even when WithHelpOnError is also set.
+func WithQuietErrors() Option {
+	return func(c *Config) { c.QuietErrors = true }
+}
+
+// WithPlusFlags enables the legacy `+x` convention, where `+x` sets the
+// boolean short flag `-x` to false and `-x` sets it to true.
+func WithPlusFlags() Option {
+	return func(c *Config) { c.PlusFlags = true }
+}
+
+// WithJSONStdin reads a JSON object from stdin, when it is piped, and uses its
+// members as values for the flags whose long names match their keys.
+func WithJSONStdin() Option {
+	return func(c *Config) { c.JSONStdin = true }
+}
+
+// WithOutput replaces os.Stdout as the writer that receives help and version text.
+func WithOutput(w io.Writer) Option {
+	return func(c *Config) { c.Output = w }
+}
+
+// WithErrorOutput replaces os.Stderr as the writer that receives the help and
+// errors printed by WithHelpOnError and WithUsageOnMissing.
+func WithErrorOutput(w io.Writer) Option {
+	return func(c *Config) { c.ErrOutput = w }
+}
+
+// WithExitFunc replaces os.Exit as the function called after help, version or
+// usage output is printed.
+func WithExitFunc(fn func(code int)) Option {
+	return func(c *Config) { c.Exit = fn }
+}
+
+// WithStdin replaces os.Stdin as the reader consulted by WithJSONStdin.
+func WithStdin(r io.Reader) Option {
+	return func(c *Config) { c.Stdin = r }
+}
+
+// WithConfigFiles reads values from the JSON config files at paths, merged in
+// order so that later files override earlier ones. Missing files are skipped.
+func WithConfigFiles(paths ...string) Option {
+	return func(c *Config) { c.ConfigFiles = append(c.ConfigFiles, paths...) }
+}
+
+// WithFlagsFile enables the built-in `--flags-file path` flag, which applies
+// the flags listed in a file as if they had been given on the command line.
+func WithFlagsFile() Option {
+	return func(c *Config) { c.FlagsFile = true }
+}
+
+// WithNormalizer registers fn to transform the raw value of the named field
+// before it is converted and stored.
+func WithNormalizer(field string, fn func(string) string) Option {
+	return func(c *Config) {
+		if c.Normalizers == nil {
+			c.Normalizers = map[string]func(string) string{}
+		}
+		c.Normalizers[field] = fn
+	}
+}
+
+// WithValidate registers fn to check the struct of the command at path once
+// its fields are bound. path is the space-separated subcommand path, such as
+// "serve" or "remote add", and is empty for the root command.
+func WithValidate(path string, fn func(cmd any) error) Option {
+	return func(c *Config) {
+		if c.Validators == nil {
+			c.Validators = map[string]func(cmd any) error{}
+		}
+		c.Validators[path] = fn
+	}
+}
+
+// WithAvailableCommands lists every valid subcommand in unknown-subcommand errors.
+func WithAvailableCommands() Option {
+	return func(c *Config) { c.ListCommands = true }
+}
+
+// WithRootOnlyHelp makes the help and version flags trigger only at the root
+// command; subcommands never intercept them.
+func WithRootOnlyHelp() Option {
+	return func(c *Config) { c.RootOnlyHelp = true }
+}
+
+// WithTranslator registers fn to localize help headings, descriptions and
+// error messages at render time.
+func WithTranslator(fn func(key, text string) string) Option {
+	return func(c *Config) { c.Translator = fn }
+}
+
+// WithRequiredFirst lists required flags before optional ones in the Options
+// section of help, keeping declaration order within each group.
+func WithRequiredFirst() Option {
+	return func(c *Config) { c.RequiredFirst = true }
+}
+
+// WithSubcommandHint replaces the hint shown below the subcommands in help.
+// Any {name} in text is replaced by the program name; an empty text hides it.
+func WithSubcommandHint(text string) Option {
+	return func(c *Config) { c.SubcommandHint = &text }
+}
+
+// WithAllErrors reports every missing argument, invalid value and unknown
+// flag of a command together instead of stopping at the first.
+func WithAllErrors() Option {
+	return func(c *Config) { c.AllErrors = true }
+}
+
+// WithBestEffort leaves fields whose value fails to convert at their default,
+// recording a warning instead of returning an error.
+func WithBestEffort() Option {
+	return func(c *Config) { c.BestEffort = true }
+}
+
+// WithArgs parses args, which exclude the program name, instead of os.Args[1:].
+func WithArgs(args []string) Option {
+	return func(c *Config) { c.Args = append([]string{}, args...) }
+}
+
+// WithColor forces ANSI styling of help and version text on or off,
+// regardless of whether the output is a terminal.
+func WithColor(on bool) Option {
+	return func(c *Config) { c.Color = &on }
+}
+
+// WithWidth wraps help text at cols columns instead of the detected width.
+func WithWidth(cols int) Option {
+	return func(c *Config) { c.Width = cols }
+}
+
+// WithHelpVerbosity renders help at level v, whatever the long argument of the
+// help builders says.
+func WithHelpVerbosity(v HelpVerbosity) Option {
+	return func(c *Config) { c.HelpVerbosity = v }
+}
+
+// WithContext passes ctx to the Run method of the command ParseAndRun invokes.
+func WithContext(ctx context.Context) Option {
+	return func(c *Config) { c.Context = ctx }
+}
@@ -0,0 +1,254 @@
+package clifford
+
+import "github.com/chriso345/clifford/internal/options"
+
+// Option configures how Parse behaves. Options are applied in the order they
+// are given.
+//
+// Usage:
+//
+//	err := clifford.Parse(&target, clifford.WithHelpOnError())
+type Option = options.Option
+
+// WithHelpOnError prints the command's help text to stderr whenever parsing
+// fails, before the error is returned to the caller.
+var WithHelpOnError = options.WithHelpOnError
+
+// WithUsageOnMissing guides users who run the tool bare: when the root command
+// is missing a required argument or, if tagged `require_subcmd:"true"`, a
+// subcommand, the help text and the error are printed to stderr and the
+// program exits with status 2. Missing arguments of subcommands are returned
+// as usual.
+var WithUsageOnMissing = options.WithUsageOnMissing
+
+// WithQuietErrors guarantees that parse errors are returned plainly, without
+// any accompanying help or usage output. It takes precedence over
+// WithHelpOnError, which makes it suitable for libraries that embed clifford
+// and want to report clean errors to their own callers.
+var WithQuietErrors = options.WithQuietErrors
+
+// WithPlusFlags enables the `+x` toggle convention used by some legacy tools:
+// `+x` sets the boolean short flag `-x` to false, while `-x` sets it to true.
+// When both forms appear, the last one on the command line wins.
+var WithPlusFlags = options.WithPlusFlags
+
+// WithJSONStdin reads a JSON object from stdin when input is piped and uses
+// its members as values for flags whose long names match the object keys,
+// at any subcommand level. This supports invocations such as:
+//
+//	echo '{"port": 80}' | app serve
+//
+// Values from stdin take precedence over defaults but are overridden by flags
+// given on the command line. Nothing is read when stdin is a terminal.
+var WithJSONStdin = options.WithJSONStdin
+
+// WithConfigFiles reads flag values from JSON config files, each an object
+// keyed by long flag name like the input to WithJSONStdin. Files are merged in
+// the order given, so later files override keys from earlier ones, which
+// supports layering a base config with environment-specific overrides:
+//
+//	clifford.WithConfigFiles("config.json", "config.production.json")
+//
+// Missing files are skipped. Config values rank below the command line and
+// JSON on stdin, but above defaults.
+var WithConfigFiles = options.WithConfigFiles
+
+// WithFlagsFile enables a built-in `--flags-file path` flag that reads a file
+// of flags, one per line, and applies them as if they had been typed on the
+// command line. This suits checked-in default flag sets:
+//
+//	# defaults.flags
+//	--port 8080
+//	--verbose
+//
+// Blank lines and lines starting with # are ignored. Each line holds a flag
+// and its value separated by a space, or `--flag=value`. The flags apply to
+// the command that --flags-file is given to, and any flag typed on the command
+// line overrides the file. Slice and map flags collect values from both.
+var WithFlagsFile = options.WithFlagsFile
+
+// WithOutput sends the help and version text printed by Parse to w instead
+// of os.Stdout, for capturing it in tests or routing it through a TUI or
+// logger. Parse still exits after printing. Error output goes to the writer
+// given to WithErrorOutput.
+//
+// Usage:
+//
+//	var buf bytes.Buffer
+//	err := clifford.Parse(&target, clifford.WithOutput(&buf))
+var WithOutput = options.WithOutput
+
+// WithWriter is WithOutput under the name used alongside New.
+var WithWriter = options.WithOutput
+
+// WithErrorOutput sends the help and errors printed by WithHelpOnError and
+// WithUsageOnMissing to w instead of os.Stderr. Like help on stdout, the text
+// is styled only when w is a terminal, or as WithColor says.
+var WithErrorOutput = options.WithErrorOutput
+
+// WithArgs makes Parse, ParseResult and Parser.Parse read args, which exclude
+// the program name, instead of os.Args[1:].
+var WithArgs = options.WithArgs
+
+// WithColor forces ANSI styling of help and version text on (true) or off
+// (false). By default output is styled only when written to a terminal.
+// Setting NO_COLOR still disables styling.
+var WithColor = options.WithColor
+
+// WithContext sets the context passed to the Run method of the command that
+// ParseAndRun invokes, such as one cancelled on an interrupt signal.
+var WithContext = options.WithContext
+
+// WithWidth wraps help descriptions and the `long_about` text at cols
+// columns. Without it, help is wrapped to the COLUMNS environment variable,
+// then to the width of the terminal on stdout, and otherwise at 80 columns.
+var WithWidth = options.WithWidth
+
+// WithExitFunc replaces os.Exit as the function Parse calls after printing
+// help or version text (with status 0) or WithUsageOnMissing output (with
+// status 2). When fn returns instead of exiting, Parse returns
+// ErrHelpRequested or ErrVersionRequested from the clifford/errors package, or
+// the missing-argument error, so servers and dispatching tools can handle
+// `--help` without ending the process:
+//
+//	err := clifford.Parse(&target, clifford.WithExitFunc(func(int) {}))
+//	if errors.Is(err, clierrors.ErrHelpRequested) {
+//		return nil
+//	}
+//
+// By default Parse calls os.Exit.
+var WithExitFunc = options.WithExitFunc
+
+// WithStdin replaces os.Stdin as the reader consulted by WithJSONStdin. Any
+// reader other than an *os.File is treated as piped input.
+var WithStdin = options.WithStdin
+
+// WithNormalizer registers fn to transform the raw string value of the named
+// field before it is converted, e.g. to trim whitespace or lowercase input.
+// The field is identified by its Go field name. Normalizers apply to values
+// from every source, including defaults.
+//
+// For the common case of expanding a leading `~` to the user's home
+// directory, tag the field with `expand_home:"true"` instead.
+var WithNormalizer = options.WithNormalizer
+
+// WithAvailableCommands makes unknown-subcommand errors enumerate every valid
+// subcommand at that level, in addition to any close-match suggestion:
+//
+//	unknown subcommand: xyz; available: serve, status
+var WithAvailableCommands = options.WithAvailableCommands
+
+// WithRootOnlyHelp makes `-h`, `--help` and `--version` trigger only when
+// they appear before any subcommand. After a subcommand they are no longer
+// intercepted, so `app serve --help` parses `serve` as usual instead of
+// printing its help.
+var WithRootOnlyHelp = options.WithRootOnlyHelp
+
+// WithTranslator registers fn to localize every user-visible string at render
+// time: help headings, descriptions and error messages. fn receives a stable
+// key alongside the default English text and returns the text to display:
+//
+//	help.usage, help.arguments, help.options, help.subcommands  section headings
+//	help.examples                                               the examples heading
+//	help.group.<group>                                          subcommand group headings
+//	help.default                                                the "default" label
+//	help.requires                                               the "use with" label
+//	help.conflicts                                              the "not with" label
+//	help.example                                                the "e.g." label
+//	help.choices                                                the "choices" label
+//	help.range, help.min, help.max                              the labels of numeric bounds
+//	help.repeatable                                             the note on count flags
+//	help.since                                                  the "since" label of flag versions
+//	help.env                                                    the "env" label of environment variables
+//	help.subcommand_hint                                        the hint below the subcommands
+//	desc.<Field>                                                a flag, argument or subcommand description
+//	desc.<Field>.<choice>                                       the description of one of a flag's choices
+//	desc                                                        the description of the command shown
+//	long_about                                                  the extended description in long help
+//	desc.help, desc.version, desc.help_subcommand               built-in descriptions
+//	error.parse, error.missing_arg, error.unknown_subcommand, error.unknown_flag,
+//	error.subcommand_required, error.flag_constraint, error.too_many_args,
+//	error.unsupported_field_type, error.invalid_value,
+//	error.invalid_choice, error.out_of_range, error.pattern,
+//	error.validation, error.definition                          error messages
+//
+// Translated errors still match with errors.As and errors.Is.
+var WithTranslator = options.WithTranslator
+
+// WithRequiredFirst lists required flags ahead of optional ones in the
+// Options section of help, so mandatory flags stand out at a glance. Flags
+// keep their declaration order within each group, and the automatic help and
+// version flags still come last.
+var WithRequiredFirst = options.WithRequiredFirst
+
+// WithSubcommandHint replaces the hint printed below the subcommand list in
+// help, which by default reads:
+//
+//	Run 'app <command> --help' for more information on a command.
+//
+// or suggests `app help <command>` when help is exposed as a subcommand. Any
+// `{name}` in text is replaced by the program name. Pass an empty string to
+// hide the hint.
+var WithSubcommandHint = options.WithSubcommandHint
+
+// WithBestEffort makes parsing tolerate values that fail to convert, such as
+// `--port abc` for an integer field. The field is left at its default, or its
+// zero value, and the failure is appended to Result.Warnings instead of being
+// returned as an error. Use ParseResult to read the warnings.
+//
+// Other errors, such as missing required arguments, still fail the parse.
+var WithBestEffort = options.WithBestEffort
+
+// WithAllErrors makes parsing report every problem with a command at once,
+// such as missing required arguments, values that fail to convert and unknown
+// flags, so users can fix them in one go. When there is more than one, the
+// error is a MultiError listing them one per line, and errors.As and
+// errors.Is still find each of them:
+//
+//	err := clifford.Parse(&target, clifford.WithAllErrors())
+//	var multi clierrors.MultiError
+//	if errors.As(err, &multi) {
+//		fmt.Println(len(multi.Errors), "problems")
+//	}
+var WithAllErrors = options.WithAllErrors
+
+// WithValidate registers fn as a validation hook for the command at path,
+// called with a pointer to the command's struct once all of its fields are
+// bound. Use it for checks that span several fields:
+//
+//	clifford.WithValidate("serve", func(cmd any) error {
+//		serve := cmd.(*ServeCmd)
+//		if (serve.Host.Value == "") != (serve.Port.Value == 0) {
+//			return errors.New("host and port must be set together")
+//		}
+//		return nil
+//	})
+//
+// path is the space-separated subcommand path, such as "serve" or
+// "remote add"; use "" for the root command. An error returned by fn stops the
+// parse and is wrapped in a ValidationError that unwraps to it.
+var WithValidate = options.WithValidate
+
+// HelpVerbosity selects how much detail help output shows. See
+// WithHelpVerbosity.
+type HelpVerbosity = options.HelpVerbosity
+
+const (
+	// HelpTerse lists the names of commands, arguments and flags only, as
+	// shown for `-h`.
+	HelpTerse = options.HelpTerse
+	// HelpNormal adds each flag's description along with its default, choices
+	// and other notes.
+	HelpNormal = options.HelpNormal
+	// HelpFull adds the `long_about` text, choice tables, environment
+	// variables and examples, as shown for `--help`.
+	HelpFull = options.HelpFull
+)
+
+// WithHelpVerbosity renders help at the given level of detail. It takes
+// precedence over the long argument of BuildHelp, WriteHelp and
+// BuildHelpWithParent, which selects HelpFull when true and HelpTerse when
+// false, and over the choice between `-h` and `--help`:
+//
+//	help, err := clifford.BuildHelp(&target, false, clifford.WithHelpVerbosity(clifford.HelpNormal))
+var WithHelpVerbosity = options.WithHelpVerbosity